@@ -0,0 +1,157 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// Access log line formats for WithAccessLogFormat
+const (
+	AccessLogJSON     = "json"
+	AccessLogLogfmt   = "logfmt"
+	AccessLogCombined = "combined"
+)
+
+// accessLogTimeFormat is the timestamp layout of the Apache combined format
+const accessLogTimeFormat = "02/Jan/2006:15:04:05 -0700"
+
+// accessLog writes one structured record per request to the configured
+// Slog. Besides the raw path it logs the matched route template, left empty
+// when no route matched, so aggregators can group by endpoint. It is
+// installed as a server-wide middleware, so it wraps every group and route
+// middleware and sees the final status and size even when one of them
+// short-circuits the handler. Timings added with AddTiming are logged in
+// the Server-Timing syntax. With WithAccessLogFormat the record is written
+// as a formatted line to the configured output instead.
+func (s *Server) accessLog() MiddlewareFunc {
+	format := s.params.GetAccessLogFormat()
+	out := &lineWriter{w: s.params.GetAccessLogOutput()}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if len(format) == 0 && s.params.GetSlog() == nil {
+				return next(c)
+			}
+
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			route := c.Path()
+			if err == echo.ErrNotFound {
+				route = ""
+			}
+
+			req := c.Request()
+			fields := slog.M{
+				"method":     req.Method,
+				"path":       req.URL.Path,
+				"route":      route,
+				"status":     c.Response().Status,
+				"bytes":      c.Response().Size,
+				"latency_ms": float64(time.Since(start)) / float64(time.Millisecond),
+			}
+			if id := RequestID(c); len(id) > 0 {
+				fields["request_id"] = id
+			}
+			if retry := RetryCount(c); retry > 0 {
+				fields["retry_count"] = retry
+			}
+			if traceID := TraceID(c); len(traceID) > 0 {
+				fields["trace_id"] = traceID
+			}
+			if timings := Timings(c); len(timings) > 0 {
+				fields["timings"] = serverTiming(timings)
+			}
+
+			switch format {
+			case AccessLogJSON:
+				fields["time"] = start.Format(time.RFC3339Nano)
+				line, _ := json.Marshal(fields)
+				out.write(string(line))
+			case AccessLogLogfmt:
+				fields["time"] = start.Format(time.RFC3339Nano)
+				out.write(logfmt(fields))
+			case AccessLogCombined:
+				out.write(combinedLine(c, start))
+			default:
+				s.log(slog.InfoLevel, "request", fields)
+			}
+
+			return err
+		}
+	}
+}
+
+// lineWriter serializes access log lines written by concurrent requests
+type lineWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (l *lineWriter) write(line string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	_, _ = io.WriteString(l.w, line+"\n")
+}
+
+// logfmt renders fields as key=value pairs sorted by key, quoting values
+// that are empty or contain spaces, quotes or equal signs
+func logfmt(fields slog.M) string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	pairs := make([]string, 0, len(keys))
+	for _, k := range keys {
+		v := fmt.Sprint(fields[k])
+		if len(v) == 0 || strings.ContainsAny(v, " \"=") {
+			v = strconv.Quote(v)
+		}
+		pairs = append(pairs, k+"="+v)
+	}
+
+	return strings.Join(pairs, " ")
+}
+
+// combinedLine renders the request in the Apache combined log format
+func combinedLine(c Context, start time.Time) string {
+	req := c.Request()
+	res := c.Response()
+
+	size := "-"
+	if res.Size > 0 {
+		size = strconv.FormatInt(res.Size, 10)
+	}
+
+	return fmt.Sprintf(`%s - - [%s] "%s %s %s" %d %s %s %s`,
+		c.RealIP(),
+		start.Format(accessLogTimeFormat),
+		req.Method, req.RequestURI, req.Proto,
+		res.Status, size,
+		strconv.Quote(orDash(req.Referer())),
+		strconv.Quote(orDash(req.UserAgent())),
+	)
+}
+
+// orDash returns "-" for empty values as the combined format expects
+func orDash(v string) string {
+	if len(v) == 0 {
+		return "-"
+	}
+	return v
+}
@@ -0,0 +1,235 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+// accessLogRecords parses the JSON access log lines written to buf
+func accessLogRecords(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	var records []map[string]any
+
+	scanner := bufio.NewScanner(buf)
+	for scanner.Scan() {
+		var record map[string]any
+		if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record)) && record["message"] == "request" {
+			records = append(records, record)
+		}
+	}
+
+	return records
+}
+
+func TestAccessLogRoute(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithAccessLog())
+	rr := NewRouters()
+	rr.AddRouter("/users/:id", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.Param("id"))
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+	_ = server.RegisterRouters(V1, rr, func(next HandlerFunc) HandlerFunc { return next })
+
+	e := server.GetEcho()
+	for _, path := range []string{"/users/42", "/missing", "/v1/missing"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		e.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	assert.NoError(t, logger.Flush())
+
+	records := accessLogRecords(t, &buf)
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, "/users/42", records[0]["path"])
+		assert.Equal(t, "/users/:id", records[0]["route"])
+		assert.Equal(t, float64(http.StatusOK), records[0]["status"])
+		assert.Equal(t, http.MethodGet, records[0]["method"])
+
+		assert.Equal(t, "/missing", records[1]["path"])
+		assert.Equal(t, "", records[1]["route"])
+		assert.Equal(t, float64(http.StatusNotFound), records[1]["status"])
+
+		assert.Equal(t, "/v1/missing", records[2]["path"])
+		assert.Equal(t, "", records[2]["route"])
+	}
+}
+
+func TestAccessLogWithoutSlog(t *testing.T) {
+	server, _ := NewServer(WithAccessLog())
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "test passed", rec.Body.String())
+}
+
+func TestAccessLogShortCircuit(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithAccessLog())
+	rr := NewRouters()
+	rr.AddRouter("/admin", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "welcome")
+		},
+	})
+
+	deny := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return c.String(http.StatusForbidden, "denied")
+		}
+	}
+	_ = server.RegisterRouters(ROOT, rr, deny)
+
+	req := httptest.NewRequest(http.MethodGet, "/admin", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+	assert.NoError(t, logger.Flush())
+
+	records := accessLogRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, float64(http.StatusForbidden), records[0]["status"])
+		assert.Equal(t, float64(len("denied")), records[0]["bytes"])
+		assert.Contains(t, records[0], "latency_ms")
+	}
+}
+
+func TestAccessLogTimings(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithAccessLog())
+	rr := NewRouters()
+	rr.AddRouter("/report", Methods{
+		http.MethodGet: func(c Context) error {
+			AddTiming(c, "db", 12*time.Millisecond)
+			AddTiming(c, "render", 500*time.Microsecond)
+			return c.String(http.StatusOK, "report")
+		},
+	})
+	rr.AddRouter("/plain", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "plain")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	for _, path := range []string{"/report", "/plain"} {
+		server.GetEcho().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+	assert.NoError(t, logger.Flush())
+
+	records := accessLogRecords(t, &buf)
+	if assert.Len(t, records, 2) {
+		assert.Equal(t, "db;dur=12, render;dur=0.5", records[0]["timings"])
+		assert.NotContains(t, records[1], "timings")
+	}
+}
+
+func TestAccessLogFormat(t *testing.T) {
+	handler := func(c Context) error {
+		AddTiming(c, "db", 12*time.Millisecond)
+		return c.String(http.StatusCreated, "created")
+	}
+
+	tests := []struct {
+		format string
+		check  func(t *testing.T, line string)
+	}{
+		{AccessLogJSON, func(t *testing.T, line string) {
+			var record map[string]any
+			if assert.NoError(t, json.Unmarshal([]byte(line), &record)) {
+				assert.Equal(t, http.MethodPost, record["method"])
+				assert.Equal(t, "/users/42", record["path"])
+				assert.Equal(t, "/users/:id", record["route"])
+				assert.Equal(t, float64(http.StatusCreated), record["status"])
+				assert.Equal(t, float64(len("created")), record["bytes"])
+				assert.Contains(t, record, "latency_ms")
+				assert.Contains(t, record, "time")
+				assert.Equal(t, "db;dur=12", record["timings"])
+			}
+		}},
+		{AccessLogLogfmt, func(t *testing.T, line string) {
+			record := map[string]string{}
+			for _, pair := range regexp.MustCompile(`(\w+)=("(?:[^"\\]|\\.)*"|\S*)`).FindAllStringSubmatch(line, -1) {
+				record[pair[1]] = pair[2]
+			}
+			assert.Equal(t, http.MethodPost, record["method"])
+			assert.Equal(t, "/users/42", record["path"])
+			assert.Equal(t, "/users/:id", record["route"])
+			assert.Equal(t, "201", record["status"])
+			assert.Equal(t, "7", record["bytes"])
+			assert.Contains(t, record, "latency_ms")
+			assert.Equal(t, `"db;dur=12"`, record["timings"])
+		}},
+		{AccessLogCombined, func(t *testing.T, line string) {
+			combined := regexp.MustCompile(`^(\S+) - - \[([^\]]+)\] "(\S+) (\S+) (\S+)" (\d{3}) (\d+|-) "([^"]*)" "([^"]*)"$`)
+			m := combined.FindStringSubmatch(line)
+			if assert.NotNil(t, m, line) {
+				assert.Equal(t, "192.0.2.1", m[1])
+				_, err := time.Parse("02/Jan/2006:15:04:05 -0700", m[2])
+				assert.NoError(t, err)
+				assert.Equal(t, http.MethodPost, m[3])
+				assert.Equal(t, "/users/42?dry=1", m[4])
+				assert.Equal(t, "HTTP/1.1", m[5])
+				assert.Equal(t, "201", m[6])
+				assert.Equal(t, "7", m[7])
+				assert.Equal(t, "https://example.com/", m[8])
+				assert.Equal(t, "test agent", m[9])
+			}
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.format, func(t *testing.T) {
+			var buf bytes.Buffer
+			server, err := NewServer(WithAccessLogFormat(tt.format, &buf))
+			assert.NoError(t, err)
+
+			rr := NewRouters()
+			rr.AddRouter("/users/:id", Methods{http.MethodPost: handler})
+			_ = server.RegisterRouters(ROOT, rr)
+
+			req := httptest.NewRequest(http.MethodPost, "/users/42?dry=1", nil)
+			req.Header.Set("Referer", "https://example.com/")
+			req.Header.Set("User-Agent", "test agent")
+			server.GetEcho().ServeHTTP(httptest.NewRecorder(), req)
+
+			lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+			if assert.Len(t, lines, 1) {
+				tt.check(t, lines[0])
+			}
+		})
+	}
+
+	_, err := NewServer(WithAccessLogFormat("xml", nil))
+	assert.Error(t, err)
+}
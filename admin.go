@@ -0,0 +1,148 @@
+package server
+
+import (
+	"net/http"
+	"reflect"
+	"runtime"
+	"runtime/pprof"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// AdminHealth is the body of the admin health endpoint
+type AdminHealth struct {
+	Started    bool   `json:"started"`
+	Ready      bool   `json:"ready"`
+	Draining   bool   `json:"draining"`
+	Addr       string `json:"addr"`
+	Goroutines int    `json:"goroutines"`
+	Invalid    string `json:"invalid,omitempty"`
+}
+
+// registerAdmin serves the introspection endpoints under prefix, each
+// guarded by auth:
+//
+//	GET {prefix}/routes       ListRoutes
+//	GET {prefix}/middlewares  Middlewares
+//	GET {prefix}/requests     RecentRequests
+//	GET {prefix}/health       AdminHealth
+//	GET {prefix}/config       the server options with secrets redacted
+//	GET {prefix}/goroutines   a dump of every goroutine stack
+//
+// The routes are hidden from ListRoutes and answer 503 until the server is
+// started.
+func (s *Server) registerAdmin(prefix string, auth MiddlewareFunc) error {
+	endpoints := []struct {
+		path    string
+		summary string
+		handler HandlerFunc
+	}{
+		{"/routes", "Registered routes", func(c Context) error {
+			return c.JSON(http.StatusOK, s.ListRoutes())
+		}},
+		{"/middlewares", "Installed middlewares", s.MiddlewaresHandler()},
+		{"/requests", "Recent requests", s.RecentRequestsHandler()},
+		{"/health", "Server health detail", s.adminHealth},
+		{"/config", "Server configuration", func(c Context) error {
+			return c.JSON(http.StatusOK, s.params.dump())
+		}},
+		{"/goroutines", "Goroutine dump", adminGoroutines},
+	}
+
+	prefix = strings.TrimSuffix(prefix, "/")
+
+	rr := NewRouters()
+	for _, ep := range endpoints {
+		if err := rr.AddRoute(RegisterRouter{
+			Path:        prefix + ep.path,
+			Methods:     Methods{http.MethodGet: ep.handler},
+			Summary:     ep.summary,
+			Middlewares: []MiddlewareFunc{auth, s.requireStarted},
+			Hidden:      true,
+			builtin:     true,
+		}); err != nil {
+			return err
+		}
+	}
+
+	return s.RegisterRouters(ROOT, rr)
+}
+
+// requireStarted refuses requests with a 503 while the server is not
+// running, e.g. when served through the echo instance before Start
+func (s *Server) requireStarted(next HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		if !s.Started() {
+			return echo.NewHTTPError(http.StatusServiceUnavailable, "server not started")
+		}
+		return next(c)
+	}
+}
+
+func (s *Server) adminHealth(c Context) error {
+	health := AdminHealth{
+		Started:    s.Started(),
+		Ready:      s.IsReady(),
+		Draining:   s.draining.Load(),
+		Addr:       s.Addr(),
+		Goroutines: runtime.NumGoroutine(),
+	}
+	if err := s.Validate(); err != nil {
+		health.Invalid = err.Error()
+	}
+	return c.JSON(http.StatusOK, health)
+}
+
+// adminGoroutines writes the stacks of all goroutines as plain text
+func adminGoroutines(c Context) error {
+	c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+	c.Response().WriteHeader(http.StatusOK)
+	return pprof.Lookup("goroutine").WriteTo(c.Response(), 2)
+}
+
+// dump lists the options by field name for the admin config endpoint. Plain
+// values are shown as is, durations as text, and values that can't be
+// represented such as functions and loggers as "set" when configured.
+// Fields whose name suggests a secret are redacted.
+func (s *ServerParams) dump() map[string]any {
+	out := map[string]any{}
+
+	v := reflect.ValueOf(s).Elem()
+	for i := 0; i < v.NumField(); i++ {
+		name, field := v.Type().Field(i).Name, v.Field(i)
+		if field.IsZero() {
+			continue
+		}
+
+		switch {
+		case sensitiveKey(name):
+			out[name] = redacted
+		case field.Type() == reflect.TypeOf(time.Duration(0)):
+			out[name] = field.Interface().(time.Duration).String()
+		case plainValue(field.Type()):
+			out[name] = field.Interface()
+		default:
+			out[name] = "set"
+		}
+	}
+
+	return out
+}
+
+// plainValue reports whether values of t are basic types or slices of
+// them, safe to expose and encode as JSON. Bytes are left out so raw
+// payloads such as the mock spec are not dumped.
+func plainValue(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	case reflect.Slice:
+		return plainValue(t.Elem())
+	}
+	return false
+}
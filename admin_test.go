@@ -0,0 +1,117 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func adminToken(next HandlerFunc) HandlerFunc {
+	return func(c Context) error {
+		if c.Request().Header.Get(echo.HeaderAuthorization) != "Bearer admin" {
+			return echo.ErrUnauthorized
+		}
+		return next(c)
+	}
+}
+
+func TestWithAdmin(t *testing.T) {
+	server, err := NewServer(
+		WithHost("127.0.0.1"),
+		WithPort("0"),
+		WithAdmin("/_admin", adminToken),
+		WithRecentRequests(10),
+		WithShutdownTimeout(5*time.Second),
+		WithCORS([]string{"https://example.com"}, nil),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{http.MethodGet: func(c Context) error { return c.NoContent(http.StatusOK) }})
+	_ = server.RegisterRouters(V1, rr)
+
+	server.Start()
+	<-server.Listening()
+	defer server.Close()
+
+	get := func(path, token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if len(token) > 0 {
+			req.Header.Set(echo.HeaderAuthorization, "Bearer "+token)
+		}
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	paths := []string{"/routes", "/middlewares", "/requests", "/health", "/config", "/goroutines"}
+	for _, path := range paths {
+		assert.Equal(t, http.StatusUnauthorized, get("/_admin"+path, "").Code, path)
+		assert.Equal(t, http.StatusUnauthorized, get("/_admin"+path, "guest").Code, path)
+	}
+
+	// the admin routes are guarded, not the rest of the server
+	assert.Equal(t, http.StatusOK, get("/v1/users", "").Code)
+
+	rec := get("/_admin/routes", "admin")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `[{"Group":1,"Method":"GET","Path":"/v1/users","Summary":""}]`, rec.Body.String())
+
+	rec = get("/_admin/middlewares", "admin")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"recent-requests"`)
+
+	rec = get("/_admin/requests", "admin")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "/v1/users")
+
+	rec = get("/_admin/health", "admin")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var health AdminHealth
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &health)) {
+		assert.True(t, health.Started)
+		assert.Equal(t, server.Addr(), health.Addr)
+		assert.False(t, health.Draining)
+		assert.Positive(t, health.Goroutines)
+	}
+
+	rec = get("/_admin/config", "admin")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var config map[string]any
+	if assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &config)) {
+		assert.Equal(t, "/_admin", config["AdminPrefix"])
+		assert.Equal(t, "set", config["AdminAuth"])
+		assert.Equal(t, "5s", config["ShutdownTimeout"])
+		assert.Equal(t, float64(10), config["RecentRequests"])
+		assert.Equal(t, []any{"https://example.com"}, config["CORSOrigins"])
+		assert.NotContains(t, config, "MaxRoutes")
+	}
+
+	rec = get("/_admin/goroutines", "admin")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "goroutine ")
+}
+
+func TestSensitiveKey(t *testing.T) {
+	assert.True(t, sensitiveKey("JWTSecret"))
+	assert.True(t, sensitiveKey("APIKeys"))
+	assert.False(t, sensitiveKey("TLSKeyFile"))
+}
+
+func TestWithAdminInvalid(t *testing.T) {
+	_, err := NewServer(WithAdmin("admin", adminToken))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithAdmin("/", adminToken))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithAdmin("/_admin", nil))
+	assert.Error(t, err)
+}
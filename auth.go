@@ -0,0 +1,111 @@
+package server
+
+import (
+	"crypto/subtle"
+	"fmt"
+
+	echojwt "github.com/labstack/echo-jwt/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// ScopedMiddleware is a middleware installed on a set of route groups, or
+// globally when Groups is empty
+type ScopedMiddleware struct {
+	Middleware MiddlewareFunc
+	Groups     []Kind
+}
+
+// AuthOption configures how an auth middleware is installed
+type AuthOption func(m *ScopedMiddleware)
+
+// ForGroups restricts an auth middleware to the routes of the given groups
+func ForGroups(groups ...Kind) AuthOption {
+	return func(m *ScopedMiddleware) {
+		m.Groups = append(m.Groups, groups...)
+	}
+}
+
+// WithJWT requires a valid JWT bearer token signed with signingKey. The
+// parsed token is stored in the context under "user".
+func WithJWT(signingKey any, opts ...AuthOption) Options {
+	return func(s *ServerParams) error {
+		if signingKey == nil {
+			return fmt.Errorf("jwt signing key is nil")
+		}
+
+		return s.addAuth(echojwt.WithConfig(echojwt.Config{
+			SigningKey: signingKey,
+		}), opts...)
+	}
+}
+
+// WithAPIKeyAuth requires the given header to carry one of the keys
+func WithAPIKeyAuth(header string, keys []string, opts ...AuthOption) Options {
+	return func(s *ServerParams) error {
+		if len(header) == 0 {
+			return fmt.Errorf("api key header is empty")
+		}
+		if len(keys) == 0 {
+			return fmt.Errorf("no api keys configured")
+		}
+
+		return s.addAuth(middleware.KeyAuthWithConfig(middleware.KeyAuthConfig{
+			KeyLookup: "header:" + header,
+			Validator: func(key string, c Context) (bool, error) {
+				for _, k := range keys {
+					if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+						return true, nil
+					}
+				}
+				return false, nil
+			},
+		}), opts...)
+	}
+}
+
+// addAuth stores an auth middleware with its scope
+func (s *ServerParams) addAuth(mw MiddlewareFunc, opts ...AuthOption) error {
+	return addScoped(&s.Auth, mw, opts...)
+}
+
+// addScoped appends mw to list with the scope set by opts
+func addScoped(list *[]ScopedMiddleware, mw MiddlewareFunc, opts ...AuthOption) error {
+	scoped := ScopedMiddleware{Middleware: mw}
+	for _, opt := range opts {
+		opt(&scoped)
+	}
+
+	for _, group := range scoped.Groups {
+		if group < ROOT || group > DOCS {
+			return fmt.Errorf("invalid group type")
+		}
+	}
+
+	*list = append(*list, scoped)
+	return nil
+}
+
+// globalScoped returns the middlewares of list not scoped to any group
+func globalScoped(list []ScopedMiddleware) []MiddlewareFunc {
+	var mws []MiddlewareFunc
+	for _, m := range list {
+		if len(m.Groups) == 0 {
+			mws = append(mws, m.Middleware)
+		}
+	}
+	return mws
+}
+
+// groupScoped returns the middlewares of list scoped to the given group
+func groupScoped(list []ScopedMiddleware, group Kind) []MiddlewareFunc {
+	var mws []MiddlewareFunc
+	for _, m := range list {
+		for _, g := range m.Groups {
+			if g == group {
+				mws = append(mws, m.Middleware)
+				break
+			}
+		}
+	}
+	return mws
+}
@@ -0,0 +1,148 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAuthForGroups(t *testing.T) {
+	secret := []byte("secret")
+
+	server, err := NewServer(
+		WithJWT(secret, ForGroups(V1)),
+		WithAPIKeyAuth("X-Docs-Key", []string{"docs-key"}, ForGroups(DOCS)),
+	)
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(V1, rr)
+	_ = server.RegisterRouters(DOCS, rr)
+	_ = server.RegisterRouters(ROOT, rr)
+
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, jwt.MapClaims{"sub": "gopher"}).SignedString(secret)
+	assert.NoError(t, err)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name         string
+		path         string
+		headers      map[string]string
+		expectedCode int
+	}{
+		{"V1 without token", "/v1/test", nil, http.StatusUnauthorized},
+		{"V1 with api key only", "/v1/test", map[string]string{"X-Docs-Key": "docs-key"}, http.StatusUnauthorized},
+		{"V1 with invalid token", "/v1/test", map[string]string{"Authorization": "Bearer invalid"}, http.StatusUnauthorized},
+		{"V1 with token", "/v1/test", map[string]string{"Authorization": "Bearer " + token}, http.StatusOK},
+		{"DOCS without key", "/docs/test", nil, http.StatusBadRequest},
+		{"DOCS with token only", "/docs/test", map[string]string{"Authorization": "Bearer " + token}, http.StatusBadRequest},
+		{"DOCS with wrong key", "/docs/test", map[string]string{"X-Docs-Key": "nope"}, http.StatusUnauthorized},
+		{"DOCS with key", "/docs/test", map[string]string{"X-Docs-Key": "docs-key"}, http.StatusOK},
+		{"ROOT is public", "/test", nil, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestAuthForRootGroup(t *testing.T) {
+	tagged := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Root", "yes")
+			return next(c)
+		}
+	}
+
+	server, err := NewServer(
+		WithAPIKeyAuth("X-Api-Key", []string{"key"}, ForGroups(ROOT)),
+		WithGroupMiddleware(ROOT, tagged),
+	)
+	assert.NoError(t, err)
+
+	ok := func(c Context) error { return c.String(http.StatusOK, "test passed") }
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{http.MethodGet: ok})
+
+	_ = server.RegisterRouters(ROOT, rr)
+	_ = server.RegisterRouters(V1, rr)
+	server.Group(ROOT).GET("/ping", ok)
+
+	serve := func(path, key string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		if len(key) > 0 {
+			req.Header.Set("X-Api-Key", key)
+		}
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	assert.Equal(t, http.StatusBadRequest, serve("/test", "").Code)
+	assert.Equal(t, http.StatusBadRequest, serve("/ping", "").Code)
+
+	rec := serve("/test", "key")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "yes", rec.Header().Get("X-Root"))
+
+	// ROOT scoped middlewares stay off the other groups
+	rec = serve("/v1/test", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("X-Root"))
+}
+
+func TestAuthGlobal(t *testing.T) {
+	server, _ := NewServer(WithAPIKeyAuth("X-Api-Key", []string{"key"}))
+
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(V2, rr)
+
+	e := server.GetEcho()
+
+	req := httptest.NewRequest(http.MethodGet, "/v2/test", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/v2/test", nil)
+	req.Header.Set("X-Api-Key", "key")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestAuthOptionsInvalid(t *testing.T) {
+	_, err := NewServer(WithJWT(nil))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithAPIKeyAuth("X-Api-Key", nil))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithAPIKeyAuth("X-Api-Key", []string{"key"}, ForGroups(999)))
+	assert.Error(t, err)
+}
@@ -0,0 +1,96 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+)
+
+// HeaderBaggage is the W3C Baggage header
+const HeaderBaggage = "baggage"
+
+// maxBaggageMembers is the W3C limit on baggage list members
+const maxBaggageMembers = 180
+
+type baggageKey struct{}
+
+// Baggage returns the W3C baggage the request carried, parsed by
+// WithBaggage. Handlers must not modify the returned map.
+func Baggage(c Context) map[string]string {
+	return BaggageFromContext(c.Request().Context())
+}
+
+// BaggageFromContext returns the baggage stored in ctx, so code holding only
+// the request context can forward it
+func BaggageFromContext(ctx context.Context) map[string]string {
+	baggage, _ := ctx.Value(baggageKey{}).(map[string]string)
+	return baggage
+}
+
+// InjectBaggage sets the baggage stored in ctx on an outbound request so it
+// flows to the next service
+func InjectBaggage(ctx context.Context, req *http.Request) {
+	baggage := BaggageFromContext(ctx)
+	if len(baggage) == 0 {
+		return
+	}
+
+	keys := make([]string, 0, len(baggage))
+	for key := range baggage {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	members := make([]string, 0, len(keys))
+	for _, key := range keys {
+		members = append(members, key+"="+url.PathEscape(baggage[key]))
+	}
+	req.Header.Set(HeaderBaggage, strings.Join(members, ","))
+}
+
+// parseBaggage parses a baggage header, skipping malformed members and
+// dropping member properties
+func parseBaggage(header string) map[string]string {
+	baggage := make(map[string]string)
+	for _, member := range strings.Split(header, ",") {
+		if len(baggage) == maxBaggageMembers {
+			break
+		}
+
+		member, _, _ = strings.Cut(member, ";")
+		key, value, ok := strings.Cut(member, "=")
+		key = strings.TrimSpace(key)
+		if !ok || len(key) == 0 {
+			continue
+		}
+
+		value, err := url.PathUnescape(strings.TrimSpace(value))
+		if err != nil {
+			continue
+		}
+		baggage[key] = value
+	}
+	return baggage
+}
+
+// propagateBaggage stores the incoming baggage in the request context
+func propagateBaggage() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			header := c.Request().Header.Values(HeaderBaggage)
+			if len(header) == 0 {
+				return next(c)
+			}
+
+			baggage := parseBaggage(strings.Join(header, ","))
+			if len(baggage) > 0 {
+				ctx := context.WithValue(c.Request().Context(), baggageKey{}, baggage)
+				c.SetRequest(c.Request().WithContext(ctx))
+			}
+
+			return next(c)
+		}
+	}
+}
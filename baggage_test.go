@@ -0,0 +1,76 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithBaggage(t *testing.T) {
+	server, _ := NewServer(WithBaggage())
+
+	var got map[string]string
+	var outbound string
+
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			got = Baggage(c)
+
+			req, _ := http.NewRequestWithContext(c.Request().Context(), http.MethodGet, "http://upstream/", nil)
+			InjectBaggage(req.Context(), req)
+			outbound = req.Header.Get(HeaderBaggage)
+
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name             string
+		headers          []string
+		expected         map[string]string
+		expectedOutbound string
+	}{
+		{
+			name:             "Parsed members",
+			headers:          []string{"tier=gold, experiment=checkout%20v2;ttl=60"},
+			expected:         map[string]string{"tier": "gold", "experiment": "checkout v2"},
+			expectedOutbound: "experiment=checkout%20v2,tier=gold",
+		},
+		{
+			name:             "Multiple headers",
+			headers:          []string{"tier=gold", "region=eu"},
+			expected:         map[string]string{"tier": "gold", "region": "eu"},
+			expectedOutbound: "region=eu,tier=gold",
+		},
+		{
+			name:             "Malformed members skipped",
+			headers:          []string{"novalue,=empty,bad=%zz,ok=1"},
+			expected:         map[string]string{"ok": "1"},
+			expectedOutbound: "ok=1",
+		},
+		{
+			name: "No baggage",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, outbound = nil, ""
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			for _, h := range tt.headers {
+				req.Header.Add(HeaderBaggage, h)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expected, got)
+			assert.Equal(t, tt.expectedOutbound, outbound)
+		})
+	}
+}
@@ -0,0 +1,130 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// bandwidthIdle is how long a client may stay idle before its limiter is
+// dropped; by then its bucket is full again anyway
+const bandwidthIdle = time.Minute
+
+// BandwidthKeyFunc returns the key identifying the client a response is
+// throttled for; an empty key leaves the response unthrottled
+type BandwidthKeyFunc func(c Context) string
+
+// DefaultBandwidthKey throttles per client IP
+func DefaultBandwidthKey(c Context) string {
+	return c.RealIP()
+}
+
+// bandwidthLimiter shares a token bucket of bytes per client across all of
+// the client's concurrent responses
+type bandwidthLimiter struct {
+	bytesPerSec int
+	keyFunc     BandwidthKeyFunc
+
+	mu        sync.Mutex
+	clients   map[string]*clientBandwidth
+	lastPrune time.Time
+}
+
+type clientBandwidth struct {
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+func newBandwidthLimiter(bytesPerSec int, keyFunc BandwidthKeyFunc) *bandwidthLimiter {
+	if keyFunc == nil {
+		keyFunc = DefaultBandwidthKey
+	}
+	return &bandwidthLimiter{
+		bytesPerSec: bytesPerSec,
+		keyFunc:     keyFunc,
+		clients:     make(map[string]*clientBandwidth),
+	}
+}
+
+// limiter returns the token bucket of the client, pruning idle clients
+func (b *bandwidthLimiter) limiter(key string, now time.Time) *rate.Limiter {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if now.Sub(b.lastPrune) > bandwidthIdle {
+		for k, cl := range b.clients {
+			if now.Sub(cl.lastSeen) > bandwidthIdle {
+				delete(b.clients, k)
+			}
+		}
+		b.lastPrune = now
+	}
+
+	cl, ok := b.clients[key]
+	if !ok {
+		cl = &clientBandwidth{limiter: rate.NewLimiter(rate.Limit(b.bytesPerSec), b.bytesPerSec)}
+		b.clients[key] = cl
+	}
+	cl.lastSeen = now
+
+	return cl.limiter
+}
+
+// middleware throttles response writes to the client's bandwidth
+func (b *bandwidthLimiter) middleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			key := b.keyFunc(c)
+			if len(key) == 0 {
+				return next(c)
+			}
+
+			res := c.Response()
+			rw := res.Writer
+			res.Writer = &throttledWriter{
+				ResponseWriter: rw,
+				limiter:        b.limiter(key, time.Now()),
+				req:            c.Request(),
+			}
+			defer func() { res.Writer = rw }()
+
+			return next(c)
+		}
+	}
+}
+
+// throttledWriter waits for the limiter before each chunk it writes, giving
+// up when the request is cancelled
+type throttledWriter struct {
+	http.ResponseWriter
+	limiter *rate.Limiter
+	req     *http.Request
+}
+
+func (w *throttledWriter) Write(b []byte) (int, error) {
+	written := 0
+	for len(b) > 0 {
+		n := min(len(b), w.limiter.Burst())
+		if err := w.limiter.WaitN(w.req.Context(), n); err != nil {
+			return written, err
+		}
+
+		m, err := w.ResponseWriter.Write(b[:n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+		b = b[n:]
+	}
+	return written, nil
+}
+
+func (w *throttledWriter) Flush() {
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *throttledWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
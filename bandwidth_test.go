@@ -0,0 +1,52 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBandwidthLimit(t *testing.T) {
+	const limit = 20000
+
+	server, err := NewServer(WithBandwidthLimit(limit, nil))
+	assert.NoError(t, err)
+	assert.Contains(t, server.Middlewares(), "bandwidth-limit")
+
+	body := strings.Repeat("x", limit+limit/2)
+	rr := NewRouters()
+	rr.AddRouter("/download", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, body)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	download := func(ip string) time.Duration {
+		req := httptest.NewRequest(http.MethodGet, "/download", nil)
+		req.RemoteAddr = ip + ":1234"
+		rec := httptest.NewRecorder()
+
+		start := time.Now()
+		server.GetEcho().ServeHTTP(rec, req)
+		elapsed := time.Since(start)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+		assert.Equal(t, len(body), rec.Body.Len())
+		return elapsed
+	}
+
+	// the first second worth of bytes is sent as a burst, the rest at the cap
+	elapsed := download("192.0.2.1")
+	assert.GreaterOrEqual(t, elapsed, 450*time.Millisecond)
+	assert.Less(t, elapsed, 2*time.Second)
+
+	// another client has its own budget and is not slowed by the first
+	start := time.Now()
+	download("192.0.2.2")
+	assert.Less(t, time.Since(start), time.Second)
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/xml"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// BindByContentType decodes the request body into out with the decoder
+// matching its Content-Type: JSON using the server serializer, XML, or
+// url-encoded and multipart forms using `form` tags. Any other type is
+// refused with a 415. out is then validated like BindValidate does, a
+// failure being returned as a 400 carrying a ValidationError.
+func BindByContentType(c Context, out any) error {
+	req := c.Request()
+
+	mediaType, _, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if err != nil {
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "missing or invalid content type").SetInternal(err)
+	}
+
+	switch mediaType {
+	case echo.MIMEApplicationJSON:
+		err = c.Echo().JSONSerializer.Deserialize(c, out)
+	case echo.MIMEApplicationXML, echo.MIMETextXML:
+		err = xml.NewDecoder(req.Body).Decode(out)
+	case echo.MIMEApplicationForm, echo.MIMEMultipartForm:
+		err = (&echo.DefaultBinder{}).BindBody(c, out)
+	default:
+		return echo.NewHTTPError(http.StatusUnsupportedMediaType, "unsupported content type "+mediaType)
+	}
+
+	if err != nil {
+		var he *echo.HTTPError
+		switch {
+		case errors.As(err, &he):
+			return he
+		case errors.Is(err, io.EOF):
+			return echo.NewHTTPError(http.StatusBadRequest, "request body is empty")
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, err.Error()).SetInternal(err)
+	}
+
+	return validateBound(c, out)
+}
+
+// strictQueryBinder is echo's binder refusing query parameters the target
+// struct has no `query` tag for, see WithStrictQueryParams
+type strictQueryBinder struct {
+	echo.DefaultBinder
+}
+
+func (b *strictQueryBinder) Bind(i any, c Context) error {
+	if err := unknownQueryParams(c, i); err != nil {
+		return err
+	}
+	return b.DefaultBinder.Bind(i, c)
+}
+
+// unknownQueryParams returns a 400 listing the query parameters with no
+// matching `query` tag in i. Like echo, query parameters are only bound for
+// GET, DELETE and HEAD, so other methods and non-struct targets pass.
+func unknownQueryParams(c Context, i any) error {
+	switch c.Request().Method {
+	case http.MethodGet, http.MethodDelete, http.MethodHead:
+	default:
+		return nil
+	}
+
+	t := reflect.TypeOf(i)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil
+	}
+
+	known := map[string]bool{}
+	queryTags(t, known)
+
+	var unknown []string
+	for name := range c.QueryParams() {
+		if !known[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) == 0 {
+		return nil
+	}
+
+	sort.Strings(unknown)
+	return echo.NewHTTPError(http.StatusBadRequest, "unknown query parameters: "+strings.Join(unknown, ", "))
+}
+
+// queryTags collects the `query` tag names of t, descending into untagged
+// struct fields as echo does when binding
+func queryTags(t reflect.Type, known map[string]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("query"), ",")
+		if len(name) > 0 {
+			known[name] = true
+			continue
+		}
+
+		ft := field.Type
+		if ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		if ft.Kind() == reflect.Struct {
+			queryTags(ft, known)
+		}
+	}
+}
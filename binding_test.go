@@ -0,0 +1,176 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type bindingOrder struct {
+	Item     string `json:"item" xml:"item" form:"item"`
+	Quantity int    `json:"quantity" xml:"quantity" form:"quantity"`
+}
+
+type bindingValidator struct{}
+
+func (bindingValidator) Validate(i any) error {
+	if o, ok := i.(*bindingOrder); ok && o.Quantity <= 0 {
+		return errors.New("quantity must be positive")
+	}
+	return nil
+}
+
+func TestBindByContentType(t *testing.T) {
+	var multipartBody bytes.Buffer
+	mw := multipart.NewWriter(&multipartBody)
+	_ = mw.WriteField("item", "book")
+	_ = mw.WriteField("quantity", "2")
+	_ = mw.Close()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		status      int
+	}{
+		{"json", echo.MIMEApplicationJSON, `{"item":"book","quantity":2}`, http.StatusOK},
+		{"json charset", echo.MIMEApplicationJSONCharsetUTF8, `{"item":"book","quantity":2}`, http.StatusOK},
+		{"xml", echo.MIMEApplicationXML, `<order><item>book</item><quantity>2</quantity></order>`, http.StatusOK},
+		{"form", echo.MIMEApplicationForm, "item=book&quantity=2", http.StatusOK},
+		{"multipart", mw.FormDataContentType(), multipartBody.String(), http.StatusOK},
+		{"malformed json", echo.MIMEApplicationJSON, `{"item":`, http.StatusBadRequest},
+		{"empty json", echo.MIMEApplicationJSON, "", http.StatusBadRequest},
+		{"invalid", echo.MIMEApplicationForm, "item=book&quantity=0", http.StatusBadRequest},
+		{"unsupported", echo.MIMETextPlain, "book 2", http.StatusUnsupportedMediaType},
+		{"missing", "", `{"item":"book","quantity":2}`, http.StatusUnsupportedMediaType},
+	}
+
+	server, _ := NewServer()
+	server.GetEcho().Validator = bindingValidator{}
+
+	rr := NewRouters()
+	rr.AddRouter("/orders", Methods{
+		http.MethodPost: func(c Context) error {
+			var order bindingOrder
+			if err := BindByContentType(c, &order); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, order)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/orders", strings.NewReader(tt.body))
+			if len(tt.contentType) > 0 {
+				req.Header.Set(echo.HeaderContentType, tt.contentType)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code, rec.Body.String())
+			if tt.status == http.StatusOK {
+				assert.JSONEq(t, `{"item":"book","quantity":2}`, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestBindByContentTypeValidation(t *testing.T) {
+	type signup struct {
+		Email string `json:"email" validate:"required,email"`
+		Age   int    `json:"age" validate:"min=18"`
+	}
+
+	post := func(server *Server, target func() any, body string) *httptest.ResponseRecorder {
+		rr := NewRouters()
+		rr.AddRouter("/signup", Methods{
+			http.MethodPost: func(c Context) error {
+				v := target()
+				if err := BindByContentType(c, v); err != nil {
+					return err
+				}
+				return c.NoContent(http.StatusNoContent)
+			},
+		})
+		_ = server.RegisterRouters(ROOT, rr)
+
+		req := httptest.NewRequest(http.MethodPost, "/signup", strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	// the default struct validator runs without WithValidator
+	server, _ := NewServer()
+	rec := post(server, func() any { return &signup{} }, `{"email":"nope","age":12}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"validation failed","fields":[
+		{"field":"email","message":"must be a valid email"},
+		{"field":"age","message":"must be at least 18"}
+	]}`, rec.Body.String())
+
+	// a custom validator error is wrapped in a ValidationError too
+	server, _ = NewServer(WithValidator(bindingValidator{}))
+	rec = post(server, func() any { return &bindingOrder{} }, `{"item":"book","quantity":0}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"quantity must be positive"}`, rec.Body.String())
+}
+
+type searchPage struct {
+	Page  int `query:"page"`
+	Limit int `query:"limit"`
+}
+
+type searchRequest struct {
+	Q      string `query:"q"`
+	Paging searchPage
+}
+
+func TestStrictQueryParams(t *testing.T) {
+	tests := []struct {
+		name   string
+		opts   []Options
+		path   string
+		status int
+		body   string
+	}{
+		{"strict known", []Options{WithStrictQueryParams()}, "/search?q=go&page=2", http.StatusOK, "go:2"},
+		{"strict unknown", []Options{WithStrictQueryParams()}, "/search?q=go&foo=bar&debug=1", http.StatusBadRequest,
+			`{"message":"unknown query parameters: debug, foo"}`},
+		{"lenient", nil, "/search?q=go&foo=bar", http.StatusOK, "go:0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := NewServer(tt.opts...)
+			rr := NewRouters()
+			rr.AddRouter("/search", Methods{
+				http.MethodGet: func(c Context) error {
+					var req searchRequest
+					if err := c.Bind(&req); err != nil {
+						return err
+					}
+					return c.String(http.StatusOK, fmt.Sprintf("%s:%d", req.Q, req.Paging.Page))
+				},
+			})
+			_ = server.RegisterRouters(ROOT, rr)
+
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.body, strings.TrimSpace(rec.Body.String()))
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bufio"
+	"net/http"
+	"sync"
+)
+
+// bufferedResponseWriter buffers the response body so that many small writes
+// reach the connection as a few large ones
+type bufferedResponseWriter struct {
+	http.ResponseWriter
+	buf *bufio.Writer
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+// Flush writes the buffered data and flushes the underlying writer, so
+// streaming handlers keep working
+func (w *bufferedResponseWriter) Flush() {
+	_ = w.buf.Flush()
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+// Unwrap returns the original writer, used by http.ResponseController
+func (w *bufferedResponseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// bufferedWriter wraps the response writer with a pooled buffered writer of
+// the given size
+func bufferedWriter(size int) MiddlewareFunc {
+	pool := sync.Pool{
+		New: func() any {
+			return &bufferedResponseWriter{buf: bufio.NewWriterSize(nil, size)}
+		},
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			res := c.Response()
+
+			w := pool.Get().(*bufferedResponseWriter)
+			w.ResponseWriter = res.Writer
+			w.buf.Reset(res.Writer)
+			res.Writer = w
+
+			defer func() {
+				_ = w.buf.Flush()
+				res.Writer = w.ResponseWriter
+				w.ResponseWriter = nil
+				w.buf.Reset(nil)
+				pool.Put(w)
+			}()
+
+			return next(c)
+		}
+	}
+}
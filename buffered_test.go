@@ -0,0 +1,121 @@
+package server
+
+import (
+	"bufio"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func chunkedRouters(chunks int) *RegisterRouters {
+	rr := NewRouters()
+	rr.AddRouter("/chunks", Methods{
+		http.MethodGet: func(c Context) error {
+			c.Response().WriteHeader(http.StatusOK)
+			chunk := []byte(strings.Repeat("x", 511) + "\n")
+			for i := 0; i < chunks; i++ {
+				if _, err := c.Response().Write(chunk); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	})
+	return rr
+}
+
+func TestBufferedWriter(t *testing.T) {
+	server, err := NewServer(WithBufferedWriter(4096))
+	assert.NoError(t, err)
+
+	_ = server.RegisterRouters(ROOT, chunkedRouters(100))
+
+	e := server.GetEcho()
+	req := httptest.NewRequest(http.MethodGet, "/chunks", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, strings.Repeat(strings.Repeat("x", 511)+"\n", 100), rec.Body.String())
+}
+
+func TestBufferedWriterFlush(t *testing.T) {
+	server, _ := NewServer(WithBufferedWriter(4096))
+
+	release := make(chan struct{})
+	rr := NewRouters()
+	rr.AddRouter("/stream", Methods{
+		http.MethodGet: func(c Context) error {
+			c.Response().WriteHeader(http.StatusOK)
+			_, _ = c.Response().Write([]byte("first\n"))
+			c.Response().Flush()
+			<-release
+			_, _ = c.Response().Write([]byte("second\n"))
+			return nil
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	ts := httptest.NewServer(server.GetEcho())
+	defer ts.Close()
+
+	res, err := http.Get(ts.URL + "/stream")
+	if !assert.NoError(t, err) {
+		close(release)
+		return
+	}
+	defer res.Body.Close()
+
+	reader := bufio.NewReader(res.Body)
+	line, err := reader.ReadString('\n')
+	assert.NoError(t, err)
+	assert.Equal(t, "first\n", line)
+
+	close(release)
+
+	rest, err := io.ReadAll(reader)
+	assert.NoError(t, err)
+	assert.Equal(t, "second\n", string(rest))
+}
+
+func TestWithBufferedWriterInvalid(t *testing.T) {
+	_, err := NewServer(WithBufferedWriter(0))
+	assert.Error(t, err)
+}
+
+func BenchmarkBufferedWriter(b *testing.B) {
+	benchmarks := []struct {
+		name string
+		opts []Options
+	}{
+		{"unbuffered", nil},
+		{"buffered", []Options{WithBufferedWriter(32 * 1024)}},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			server, _ := NewServer(bm.opts...)
+			_ = server.RegisterRouters(ROOT, chunkedRouters(512))
+
+			ts := httptest.NewServer(server.GetEcho())
+			defer ts.Close()
+
+			b.ReportAllocs()
+			b.ResetTimer()
+
+			for i := 0; i < b.N; i++ {
+				res, err := http.Get(ts.URL + "/chunks")
+				if err != nil {
+					b.Fatal(err)
+				}
+				_, _ = io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// canonicalHost redirects requests addressed to any other host
+type canonicalHost struct {
+	scheme  string
+	host    string
+	proxies []*net.IPNet
+}
+
+// parseCanonicalHost accepts either a bare host ("example.com") or a URL
+// ("https://example.com") which also forces the scheme
+func parseCanonicalHost(target string) (*canonicalHost, error) {
+	target = strings.TrimSpace(target)
+	if !strings.Contains(target, "://") {
+		if len(target) == 0 || strings.ContainsAny(target, "/?#") {
+			return nil, fmt.Errorf("invalid canonical host: %q", target)
+		}
+		return &canonicalHost{host: strings.ToLower(target)}, nil
+	}
+
+	u, err := url.Parse(target)
+	if err != nil {
+		return nil, fmt.Errorf("invalid canonical host: %w", err)
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || len(u.Host) == 0 || strings.Trim(u.Path, "/") != "" {
+		return nil, fmt.Errorf("invalid canonical host: %q", target)
+	}
+
+	return &canonicalHost{scheme: u.Scheme, host: strings.ToLower(u.Host)}, nil
+}
+
+// trusted reports whether the direct peer is one of the trusted proxies
+func (ch *canonicalHost) trusted(r *http.Request) bool {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		host = r.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, proxy := range ch.proxies {
+		if proxy.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// middleware 301-redirects requests whose host or scheme differ from the
+// canonical ones. Forwarded headers are only honored from trusted proxies.
+func (ch *canonicalHost) middleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			req := c.Request()
+
+			host, scheme := req.Host, "http"
+			if req.TLS != nil {
+				scheme = "https"
+			}
+
+			if ch.trusted(req) {
+				if fwd := req.Header.Get("X-Forwarded-Host"); len(fwd) > 0 {
+					host = strings.TrimSpace(strings.Split(fwd, ",")[0])
+				}
+				if proto := req.Header.Get(echo.HeaderXForwardedProto); len(proto) > 0 {
+					scheme = strings.ToLower(strings.TrimSpace(proto))
+				}
+			}
+
+			target := scheme
+			if len(ch.scheme) > 0 {
+				target = ch.scheme
+			}
+
+			if strings.EqualFold(host, ch.host) && target == scheme {
+				return next(c)
+			}
+
+			return c.Redirect(http.StatusMovedPermanently, target+"://"+ch.host+req.RequestURI)
+		}
+	}
+}
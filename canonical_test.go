@@ -0,0 +1,99 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCanonicalHost(t *testing.T) {
+	tests := []struct {
+		name             string
+		canonical        string
+		host             string
+		remoteAddr       string
+		headers          map[string]string
+		expectedCode     int
+		expectedLocation string
+	}{
+		{
+			name:             "Redirect www to apex",
+			canonical:        "example.com",
+			host:             "www.example.com",
+			expectedCode:     http.StatusMovedPermanently,
+			expectedLocation: "http://example.com/test?q=1",
+		},
+		{
+			name:         "Canonical host is served",
+			canonical:    "example.com",
+			host:         "EXAMPLE.com",
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:             "Redirect http to canonical https",
+			canonical:        "https://example.com",
+			host:             "example.com",
+			expectedCode:     http.StatusMovedPermanently,
+			expectedLocation: "https://example.com/test?q=1",
+		},
+		{
+			name:         "Forwarded host from trusted proxy",
+			canonical:    "https://example.com",
+			host:         "10.0.0.2:8080",
+			remoteAddr:   "10.0.0.1:1234",
+			headers:      map[string]string{"X-Forwarded-Host": "example.com", "X-Forwarded-Proto": "https"},
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:             "Forwarded host from untrusted client",
+			canonical:        "example.com",
+			host:             "evil.com",
+			headers:          map[string]string{"X-Forwarded-Host": "example.com"},
+			expectedCode:     http.StatusMovedPermanently,
+			expectedLocation: "http://example.com/test?q=1",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(WithCanonicalHost(tt.canonical, "10.0.0.0/8"))
+			assert.NoError(t, err)
+
+			rr := NewRouters()
+			rr.AddRouter("/test", Methods{
+				http.MethodGet: func(c Context) error {
+					return c.String(http.StatusOK, "test passed")
+				},
+			})
+
+			_ = server.RegisterRouters(ROOT, rr)
+
+			req := httptest.NewRequest(http.MethodGet, "/test?q=1", nil)
+			req.Host = tt.host
+			if tt.remoteAddr != "" {
+				req.RemoteAddr = tt.remoteAddr
+			}
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedLocation, rec.Header().Get("Location"))
+		})
+	}
+}
+
+func TestWithCanonicalHostInvalid(t *testing.T) {
+	_, err := NewServer(WithCanonicalHost(""))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithCanonicalHost("ftp://example.com"))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithCanonicalHost("example.com", "not-a-cidr"))
+	assert.Error(t, err)
+}
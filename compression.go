@@ -0,0 +1,268 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/andybalholm/brotli"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	EncodingGzip   = "gzip"
+	EncodingBrotli = "br"
+)
+
+// compression negotiates the response encoding from Accept-Encoding and the
+// server preference order in encodings, which defaults to gzip only. The
+// client's q-values win, ties go to the earlier server encoding. Requests
+// matched by skip, protocol upgrades and opted out routes are served as is,
+// and so are bodies of an already compressed or streamed content type or
+// shorter than minLength.
+func (s *Server) compression(level int, encodings []string, minLength int, skip func(c Context) bool) MiddlewareFunc {
+	if len(encodings) == 0 {
+		encodings = []string{EncodingGzip}
+	}
+
+	quality := brotli.DefaultCompression
+	if level >= 0 {
+		quality = level
+	} else if level == gzip.HuffmanOnly {
+		quality = brotli.BestSpeed
+	}
+
+	pools := map[string]*sync.Pool{
+		EncodingGzip: {New: func() any {
+			w, _ := gzip.NewWriterLevel(io.Discard, level)
+			return w
+		}},
+		EncodingBrotli: {New: func() any {
+			return brotli.NewWriterLevel(io.Discard, quality)
+		}},
+	}
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if s.skipCompression(c) || (skip != nil && skip(c)) || isUpgrade(c.Request()) {
+				return next(c)
+			}
+
+			c.Response().Header().Add(echo.HeaderVary, echo.HeaderAcceptEncoding)
+
+			encoding := negotiateEncoding(c.Request().Header.Get(echo.HeaderAcceptEncoding), encodings)
+			if len(encoding) == 0 {
+				return next(c)
+			}
+
+			pool := pools[encoding]
+			enc := pool.Get().(encoder)
+			defer pool.Put(enc)
+
+			return compress(c, next, encoding, enc, minLength)
+		}
+	}
+}
+
+// isUpgrade reports whether the request asks to switch protocols, e.g. to
+// a WebSocket, which needs the raw connection
+func isUpgrade(req *http.Request) bool {
+	for _, v := range req.Header.Values(echo.HeaderConnection) {
+		for _, token := range strings.Split(v, ",") {
+			if strings.EqualFold(strings.TrimSpace(token), "upgrade") {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// incompressible lists the content types already compressed, and the event
+// streams that must reach the client as soon as they are flushed
+var incompressible = []string{
+	"image/", "video/", "audio/", "font/woff",
+	"application/zip", "application/gzip", "application/x-gzip",
+	"application/x-brotli", "application/x-7z-compressed",
+	"application/x-rar-compressed", "application/pdf",
+	"text/event-stream",
+}
+
+// compressible reports whether a body of contentType is worth compressing
+func compressible(contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.HasPrefix(contentType, "image/svg+xml") {
+		return true
+	}
+	for _, prefix := range incompressible {
+		if strings.HasPrefix(contentType, prefix) {
+			return false
+		}
+	}
+	return true
+}
+
+// negotiateEncoding picks the supported encoding with the highest q-value
+// in the Accept-Encoding header, or "" when none is acceptable
+func negotiateEncoding(header string, supported []string) string {
+	accepted := map[string]float64{}
+	for _, part := range strings.Split(header, ",") {
+		name, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if len(name) == 0 {
+			continue
+		}
+
+		q := 1.0
+		if v, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if f, err := strconv.ParseFloat(v, 64); err == nil {
+				q = f
+			}
+		}
+		accepted[strings.ToLower(name)] = q
+	}
+
+	best, bestQ := "", 0.0
+	for _, encoding := range supported {
+		q, ok := accepted[encoding]
+		if !ok {
+			q, ok = accepted["*"]
+		}
+		if ok && q > bestQ {
+			best, bestQ = encoding, q
+		}
+	}
+
+	return best
+}
+
+// encoder is the common interface of the gzip and brotli writers
+type encoder interface {
+	io.WriteCloser
+	Flush() error
+	Reset(w io.Writer)
+}
+
+// compress runs next with the response body encoded by enc. Headers and up
+// to minLength bytes are held back until it is known whether the body gets
+// compressed, so bodiless responses, errors and small bodies are sent
+// untouched.
+func compress(c Context, next HandlerFunc, encoding string, enc encoder, minLength int) error {
+	res := c.Response()
+
+	rw := res.Writer
+	cw := &compressWriter{ResponseWriter: rw, encoding: encoding, enc: enc, minLength: minLength}
+	res.Writer = cw
+
+	defer func() {
+		cw.finish()
+		res.Writer = rw
+	}()
+
+	return next(c)
+}
+
+// compressWriter decides on the first writes whether to compress the body,
+// mirroring echo's gzip response writer
+type compressWriter struct {
+	http.ResponseWriter
+
+	encoding  string
+	enc       encoder
+	minLength int
+
+	buf         []byte
+	code        int
+	wroteHeader bool
+	decided     bool
+	compressing bool
+}
+
+func (w *compressWriter) WriteHeader(code int) {
+	if w.decided {
+		w.ResponseWriter.WriteHeader(code)
+		return
+	}
+	w.wroteHeader = true
+	w.code = code
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if !w.decided {
+		if w.Header().Get(echo.HeaderContentType) == "" {
+			w.Header().Set(echo.HeaderContentType, http.DetectContentType(b))
+		}
+
+		switch {
+		case !compressible(w.Header().Get(echo.HeaderContentType)),
+			w.Header().Get(echo.HeaderContentEncoding) != "":
+			w.decide(false)
+		case len(w.buf)+len(b) < w.minLength:
+			w.buf = append(w.buf, b...)
+			return len(b), nil
+		default:
+			w.decide(true)
+		}
+	}
+
+	if w.compressing {
+		return w.enc.Write(b)
+	}
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush sends what was written so far, compressing it when the content type
+// allows since a streamed body has no known length
+func (w *compressWriter) Flush() {
+	if !w.decided {
+		w.decide(compressible(w.Header().Get(echo.HeaderContentType)) &&
+			w.Header().Get(echo.HeaderContentEncoding) == "")
+	}
+	if w.compressing {
+		_ = w.enc.Flush()
+	}
+	_ = http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// decide sends the held back headers and buffered bytes, through the
+// encoder when compressing
+func (w *compressWriter) decide(compressing bool) {
+	w.decided = true
+	w.compressing = compressing
+
+	if compressing {
+		w.Header().Set(echo.HeaderContentEncoding, w.encoding)
+		w.Header().Del(echo.HeaderContentLength)
+		w.enc.Reset(w.ResponseWriter)
+	}
+	if w.wroteHeader {
+		w.ResponseWriter.WriteHeader(w.code)
+	}
+
+	if len(w.buf) > 0 {
+		buf := w.buf
+		w.buf = nil
+		if compressing {
+			_, _ = w.enc.Write(buf)
+		} else {
+			_, _ = w.ResponseWriter.Write(buf)
+		}
+	}
+}
+
+// finish sends a body left below minLength as is, or completes the
+// compressed stream
+func (w *compressWriter) finish() {
+	switch {
+	case w.compressing:
+		_ = w.enc.Close()
+		w.enc.Reset(io.Discard)
+	case !w.decided && (w.wroteHeader || len(w.buf) > 0):
+		w.decide(false)
+	}
+}
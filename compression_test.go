@@ -0,0 +1,253 @@
+package server
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+	"github.com/gorilla/websocket"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCompressionOptOut(t *testing.T) {
+	server, err := NewServer(WithCompression(gzip.DefaultCompression))
+	assert.NoError(t, err)
+
+	body := strings.Repeat("compress me ", 1000)
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, body)
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/compressed", Methods{http.MethodGet: handler})
+	rr.AddRoute(RegisterRouter{
+		Path:          "/raw",
+		Methods:       Methods{http.MethodGet: handler},
+		NoCompression: true,
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+	_ = server.RegisterRouters(V1, rr)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name             string
+		path             string
+		expectedEncoding string
+	}{
+		{"Compressed route", "/compressed", "gzip"},
+		{"Opted out route", "/raw", ""},
+		{"Compressed group route", "/v1/compressed", "gzip"},
+		{"Opted out group route", "/v1/raw", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", "gzip")
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedEncoding, rec.Header().Get("Content-Encoding"))
+
+			var reader io.Reader = rec.Body
+			if tt.expectedEncoding == "gzip" {
+				gz, err := gzip.NewReader(rec.Body)
+				if !assert.NoError(t, err) {
+					return
+				}
+				reader = gz
+			}
+
+			got, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(got))
+		})
+	}
+}
+
+func TestWithCompressionInvalid(t *testing.T) {
+	_, err := NewServer(WithCompression(42))
+	assert.Error(t, err)
+}
+
+func TestCompressionBrotli(t *testing.T) {
+	server, err := NewServer(WithCompression(gzip.DefaultCompression, EncodingBrotli, EncodingGzip))
+	assert.NoError(t, err)
+
+	body := strings.Repeat("compress me ", 1000)
+	rr := NewRouters()
+	rr.AddRouter("/large", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, body)
+		},
+	})
+	rr.AddRouter("/empty", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.NoContent(http.StatusNoContent)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name             string
+		path             string
+		acceptEncoding   string
+		expectedEncoding string
+	}{
+		{"Brotli", "/large", "br", "br"},
+		{"Brotli preferred by server", "/large", "gzip, br", "br"},
+		{"Gzip preferred by client", "/large", "gzip, br;q=0.5", "gzip"},
+		{"Gzip fallback", "/large", "gzip, deflate", "gzip"},
+		{"Brotli refused", "/large", "br;q=0, gzip", "gzip"},
+		{"Identity", "/large", "", ""},
+		{"No body", "/empty", "br", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", tt.acceptEncoding)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedEncoding, rec.Header().Get("Content-Encoding"))
+			assert.Contains(t, rec.Header().Values("Vary"), "Accept-Encoding")
+			if tt.path == "/empty" {
+				assert.Equal(t, http.StatusNoContent, rec.Code)
+				assert.Zero(t, rec.Body.Len())
+				return
+			}
+
+			var reader io.Reader = rec.Body
+			switch tt.expectedEncoding {
+			case "br":
+				reader = brotli.NewReader(rec.Body)
+			case "gzip":
+				gz, err := gzip.NewReader(rec.Body)
+				if !assert.NoError(t, err) {
+					return
+				}
+				reader = gz
+			}
+
+			decoded, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, body, string(decoded))
+		})
+	}
+}
+
+func TestCompressionSkips(t *testing.T) {
+	server, err := NewServer(
+		WithCompression(gzip.DefaultCompression, EncodingBrotli, EncodingGzip),
+		WithCompressionMinLength(1024),
+		WithCompressionSkipper(func(c Context) bool {
+			return strings.HasPrefix(c.Path(), "/raw")
+		}),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	large := strings.Repeat("compress me ", 1000)
+	rr := NewRouters()
+	rr.AddRouter("/large", Methods{http.MethodGet: func(c Context) error {
+		return c.String(http.StatusOK, large)
+	}})
+	rr.AddRouter("/chunked", Methods{http.MethodGet: func(c Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlain)
+		c.Response().WriteHeader(http.StatusOK)
+		for i := 0; i < 1000; i++ {
+			_, _ = c.Response().Write([]byte("compress me "))
+		}
+		return nil
+	}})
+	rr.AddRouter("/small", Methods{http.MethodGet: func(c Context) error {
+		return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+	}})
+	rr.AddRouter("/image", Methods{http.MethodGet: func(c Context) error {
+		return c.Blob(http.StatusOK, "image/png", []byte(large))
+	}})
+	rr.AddRouter("/events", Methods{http.MethodGet: func(c Context) error {
+		c.Response().Header().Set(echo.HeaderContentType, "text/event-stream")
+		c.Response().WriteHeader(http.StatusOK)
+		_, _ = c.Response().Write([]byte("data: " + large + "\n\n"))
+		c.Response().Flush()
+		return nil
+	}})
+	rr.AddRouter("/raw", Methods{http.MethodGet: func(c Context) error {
+		return c.String(http.StatusOK, large)
+	}})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		path             string
+		expectedEncoding string
+		expectedBody     string
+	}{
+		{"/large", "br", large},
+		{"/chunked", "br", large},
+		{"/small", "", "{\"status\":\"ok\"}\n"},
+		{"/image", "", large},
+		{"/events", "", "data: " + large + "\n\n"},
+		{"/raw", "", large},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			req.Header.Set("Accept-Encoding", "br, gzip")
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, tt.expectedEncoding, rec.Header().Get("Content-Encoding"))
+
+			var reader io.Reader = rec.Body
+			if tt.expectedEncoding == "br" {
+				reader = brotli.NewReader(rec.Body)
+			}
+			got, err := io.ReadAll(reader)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedBody, string(got))
+		})
+	}
+
+	_, err = NewServer(WithCompressionMinLength(-1))
+	assert.Error(t, err)
+	_, err = NewServer(WithCompressionSkipper(nil))
+	assert.Error(t, err)
+}
+
+func TestCompressionWebSocket(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithCompression(gzip.DefaultCompression))
+	defer server.Close()
+
+	rr := NewRouters()
+	_ = rr.AddWebSocket("/ws", func(conn *websocket.Conn) error {
+		return conn.WriteMessage(websocket.TextMessage, []byte("hello"))
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	header := http.Header{"Accept-Encoding": []string{"gzip"}}
+	server.Start()
+	<-server.Listening()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+server.Addr()+"/ws", header)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, msg, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(msg))
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/labstack/echo/v4"
+)
+
+// uuidV4 matches a canonical UUID version 4
+var uuidV4 = regexp.MustCompile(`^[0-9a-fA-F]{8}-[0-9a-fA-F]{4}-4[0-9a-fA-F]{3}-[89abAB][0-9a-fA-F]{3}-[0-9a-fA-F]{12}$`)
+
+// IsUUIDv4 reports whether id is a canonical UUID version 4
+func IsUUIDv4(id string) bool {
+	return uuidV4.MatchString(id)
+}
+
+// WithRequireCorrelationID rejects with 400 requests whose header is missing
+// or fails validate. The header defaults to X-Correlation-Id and validate to
+// IsUUIDv4. It is installed as the "correlation-id" middleware, right after
+// auth, and like auth applies to every route unless scoped with ForGroups.
+func WithRequireCorrelationID(header string, validate func(string) bool, opts ...AuthOption) Options {
+	if len(header) == 0 {
+		header = echo.HeaderXCorrelationID
+	}
+	if validate == nil {
+		validate = IsUUIDv4
+	}
+
+	return func(s *ServerParams) error {
+		return addScoped(&s.RequireCorrelationID, requireCorrelationID(header, validate), opts...)
+	}
+}
+
+// requireCorrelationID checks the correlation ID header of each request
+func requireCorrelationID(header string, validate func(string) bool) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			id := c.Request().Header.Get(header)
+			if len(id) == 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "missing "+header+" header")
+			}
+			if !validate(id) {
+				return echo.NewHTTPError(http.StatusBadRequest, "invalid "+header+" header")
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequireCorrelationID(t *testing.T) {
+	server, err := NewServer(WithRequireCorrelationID("", nil, ForGroups(API)))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/orders", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.NoContent(http.StatusOK)
+		},
+	})
+	_ = server.RegisterRouters(API, rr)
+	_ = server.RegisterRouters(V1, rr)
+
+	tests := []struct {
+		name   string
+		path   string
+		id     string
+		status int
+	}{
+		{"Valid UUID", "/api/orders", "3f2c1b9e-8a4d-4c6f-9b1e-2d7a5c3e8f10", http.StatusOK},
+		{"Not a UUID", "/api/orders", "order-123", http.StatusBadRequest},
+		{"UUID v1", "/api/orders", "3f2c1b9e-8a4d-1c6f-9b1e-2d7a5c3e8f10", http.StatusBadRequest},
+		{"Missing", "/api/orders", "", http.StatusBadRequest},
+		{"Unscoped group", "/v1/orders", "", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			if len(tt.id) > 0 {
+				req.Header.Set(echo.HeaderXCorrelationID, tt.id)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
+
+func TestRequireCorrelationIDNotAuth(t *testing.T) {
+	server, err := NewServer(WithRequireCorrelationID("", nil))
+	assert.NoError(t, err)
+
+	assert.Contains(t, server.Middlewares(), "correlation-id")
+	assert.NotContains(t, server.Middlewares(), "auth")
+	assert.Empty(t, server.params.GetAuth())
+
+	config := server.params.dump()
+	assert.Contains(t, config, "RequireCorrelationID")
+	assert.NotContains(t, config, "Auth")
+}
+
+func TestRequireCorrelationIDCustomValidator(t *testing.T) {
+	server, _ := NewServer(WithRequireCorrelationID("X-Trace", func(id string) bool {
+		return len(id) == 8
+	}))
+
+	rr := NewRouters()
+	rr.AddRouter("/", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.NoContent(http.StatusOK)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	for id, status := range map[string]int{"abcdefgh": http.StatusOK, "abc": http.StatusBadRequest} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		req.Header.Set("X-Trace", id)
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		assert.Equal(t, status, rec.Code, id)
+	}
+}
@@ -0,0 +1,21 @@
+package server
+
+import "github.com/labstack/echo/v4/middleware"
+
+// useCORS reports whether WithCORS was configured
+func (s *ServerParams) useCORS() bool {
+	return len(s.GetCORSOrigins()) > 0 || s.GetCORSOriginFunc() != nil
+}
+
+// cors builds the CORS middleware. An origin func takes precedence over the
+// static list: allowed origins are reflected back with Vary: Origin so
+// caches keep per-origin responses apart, rejected origins get no CORS
+// headers and an error from the func surfaces through the error handler.
+// It runs ahead of auth so preflight requests are answered without
+// credentials.
+func (s *ServerParams) cors() MiddlewareFunc {
+	return middleware.CORSWithConfig(middleware.CORSConfig{
+		AllowOrigins:    s.GetCORSOrigins(),
+		AllowOriginFunc: s.GetCORSOriginFunc(),
+	})
+}
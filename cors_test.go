@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCORSOriginFunc(t *testing.T) {
+	tenants := map[string]bool{"https://acme.example.com": true}
+	server, err := NewServer(WithCORS(nil, func(origin string) (bool, error) {
+		return tenants[origin], nil
+	}))
+	assert.NoError(t, err)
+	assert.Contains(t, server.Middlewares(), "cors")
+
+	rr := NewRouters()
+	rr.AddRouter("/data", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "data")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	get := func(method, origin string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/data", nil)
+		req.Header.Set(echo.HeaderOrigin, origin)
+		if method == http.MethodOptions {
+			req.Header.Set(echo.HeaderAccessControlRequestMethod, http.MethodGet)
+		}
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec := get(http.MethodGet, "https://acme.example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "https://acme.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+	assert.Contains(t, rec.Header().Values(echo.HeaderVary), echo.HeaderOrigin)
+
+	rec = get(http.MethodOptions, "https://acme.example.com")
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://acme.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	rec = get(http.MethodGet, "https://evil.example.com")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	rec = get(http.MethodOptions, "https://evil.example.com")
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
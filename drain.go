@@ -0,0 +1,31 @@
+package server
+
+import (
+	"io"
+	"sync"
+)
+
+// drainBody discards up to limit bytes of request body left unread by the
+// handler and closes it, so the connection can be reused. The body is
+// drained just before the response is written, since net/http gives up on
+// keep-alive for large unread bodies once the headers are sent.
+func drainBody(limit int64) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			body := c.Request().Body
+
+			var once sync.Once
+			drain := func() {
+				once.Do(func() {
+					_, _ = io.CopyN(io.Discard, body, limit)
+					_ = body.Close()
+				})
+			}
+
+			c.Response().Before(drain)
+			defer drain()
+
+			return next(c)
+		}
+	}
+}
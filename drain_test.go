@@ -0,0 +1,70 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDrainBodyKeepsConnectionAlive(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []Options
+		expectedReused bool
+	}{
+		{"Without drain", nil, false},
+		{"With drain", []Options{WithBodyDrain(4 << 20)}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := NewServer(tt.opts...)
+			rr := NewRouters()
+			rr.AddRouter("/ignore", Methods{
+				http.MethodPost: func(c Context) error {
+					return c.String(http.StatusOK, "ignored")
+				},
+			})
+
+			_ = server.RegisterRouters(ROOT, rr)
+
+			ts := httptest.NewServer(server.GetEcho())
+			defer ts.Close()
+
+			body := bytes.Repeat([]byte("x"), 1<<20)
+
+			var reused []bool
+			trace := &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					reused = append(reused, info.Reused)
+				},
+			}
+
+			for i := 0; i < 2; i++ {
+				req, _ := http.NewRequest(http.MethodPost, ts.URL+"/ignore", bytes.NewReader(body))
+				req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+				res, err := ts.Client().Do(req)
+				if !assert.NoError(t, err) {
+					return
+				}
+				_, _ = io.Copy(io.Discard, res.Body)
+				res.Body.Close()
+
+				assert.Equal(t, http.StatusOK, res.StatusCode)
+			}
+
+			assert.Equal(t, []bool{false, tt.expectedReused}, reused)
+		})
+	}
+}
+
+func TestWithBodyDrainInvalid(t *testing.T) {
+	_, err := NewServer(WithBodyDrain(0))
+	assert.Error(t, err)
+}
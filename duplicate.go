@@ -0,0 +1,93 @@
+package server
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// DuplicateKeyFunc returns the key identifying a request for duplicate
+// detection; an empty key skips the request
+type DuplicateKeyFunc func(c Context) string
+
+// DefaultDuplicateKey uses the Idempotency-Key header when present, falling
+// back to the client IP, method and request URI
+func DefaultDuplicateKey(c Context) string {
+	if key := c.Request().Header.Get("Idempotency-Key"); len(key) > 0 {
+		return key
+	}
+	return c.RealIP() + " " + c.Request().Method + " " + c.Request().RequestURI
+}
+
+// duplicateDetector remembers request keys seen within a window
+type duplicateDetector struct {
+	window  time.Duration
+	keyFunc DuplicateKeyFunc
+
+	mu        sync.Mutex
+	seen      map[string]time.Time
+	lastPrune time.Time
+
+	count atomic.Uint64
+}
+
+func newDuplicateDetector(window time.Duration, keyFunc DuplicateKeyFunc) *duplicateDetector {
+	if keyFunc == nil {
+		keyFunc = DefaultDuplicateKey
+	}
+	return &duplicateDetector{
+		window:  window,
+		keyFunc: keyFunc,
+		seen:    make(map[string]time.Time),
+	}
+}
+
+// observe records the key and reports whether it was already seen within
+// the window
+func (d *duplicateDetector) observe(key string, now time.Time) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if now.Sub(d.lastPrune) > d.window {
+		for k, t := range d.seen {
+			if now.Sub(t) > d.window {
+				delete(d.seen, k)
+			}
+		}
+		d.lastPrune = now
+	}
+
+	last, ok := d.seen[key]
+	d.seen[key] = now
+
+	return ok && now.Sub(last) <= d.window
+}
+
+// detectDuplicates counts and logs duplicate requests without blocking them
+func (s *Server) detectDuplicates() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			key := s.duplicates.keyFunc(c)
+			if len(key) > 0 && s.duplicates.observe(key, time.Now()) {
+				s.duplicates.count.Add(1)
+				s.log(slog.WarnLevel, "duplicate request", slog.M{
+					"key":    key,
+					"method": c.Request().Method,
+					"path":   c.Request().URL.Path,
+				})
+			}
+			return next(c)
+		}
+	}
+}
+
+// DuplicateRequests returns how many duplicate requests were seen. It is
+// always zero unless the server was created with WithDuplicateDetection.
+func (s *Server) DuplicateRequests() uint64 {
+	if s.duplicates == nil {
+		return 0
+	}
+	return s.duplicates.count.Load()
+}
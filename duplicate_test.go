@@ -0,0 +1,63 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDuplicateDetection(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithDuplicateDetection(time.Minute, nil))
+	rr := NewRouters()
+	rr.AddRouter("/orders", Methods{
+		http.MethodPost: func(c Context) error {
+			return c.String(http.StatusCreated, "created")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+	send := func(key string) int {
+		req := httptest.NewRequest(http.MethodPost, "/orders", nil)
+		req.Header.Set("Idempotency-Key", key)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusCreated, send("order-1"))
+	assert.Equal(t, uint64(0), server.DuplicateRequests())
+
+	assert.Equal(t, http.StatusCreated, send("order-1"))
+	assert.Equal(t, uint64(1), server.DuplicateRequests())
+
+	assert.Equal(t, http.StatusCreated, send("order-2"))
+	assert.Equal(t, uint64(1), server.DuplicateRequests())
+
+	assert.NoError(t, logger.Flush())
+	assert.Contains(t, buf.String(), "duplicate request")
+	assert.Contains(t, buf.String(), `"key":"order-1"`)
+}
+
+func TestDuplicateDetectorWindow(t *testing.T) {
+	d := newDuplicateDetector(time.Second, nil)
+	now := time.Now()
+
+	assert.False(t, d.observe("a", now))
+	assert.True(t, d.observe("a", now.Add(500*time.Millisecond)))
+	assert.False(t, d.observe("a", now.Add(2*time.Second)))
+}
+
+func TestWithDuplicateDetectionInvalid(t *testing.T) {
+	_, err := NewServer(WithDuplicateDetection(0, nil))
+	assert.Error(t, err)
+}
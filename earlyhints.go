@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// EarlyHints writes a 103 Early Hints interim response carrying the given
+// Link header values, so the client can start fetching resources before the
+// final response is ready. Interim responses are only defined for HTTP/1.1
+// and later, so the call is a no-op for HTTP/1.0 clients.
+func EarlyHints(c Context, links []string) error {
+	if len(links) == 0 || !c.Request().ProtoAtLeast(1, 1) {
+		return nil
+	}
+
+	res := c.Response()
+	if res.Committed {
+		return fmt.Errorf("early hints must be sent before the response is committed")
+	}
+
+	for _, link := range links {
+		res.Header().Add("Link", link)
+	}
+
+	// write straight to the underlying writer: echo marks the response as
+	// committed on WriteHeader, which would swallow the final status
+	res.Writer.WriteHeader(http.StatusEarlyHints)
+
+	return nil
+}
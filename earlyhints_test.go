@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/http/httptrace"
+	"net/textproto"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEarlyHints(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			if err := EarlyHints(c, []string{"</style.css>; rel=preload; as=style"}); err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	ts := httptest.NewServer(server.GetEcho())
+	defer ts.Close()
+
+	var codes []int
+	var links []string
+	trace := &httptrace.ClientTrace{
+		Got1xxResponse: func(code int, header textproto.MIMEHeader) error {
+			codes = append(codes, code)
+			links = append(links, header.Values("Link")...)
+			return nil
+		},
+	}
+
+	req, _ := http.NewRequest(http.MethodGet, ts.URL+"/test", nil)
+	req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+	res, err := http.DefaultClient.Do(req)
+	assert.NoError(t, err)
+	defer res.Body.Close()
+
+	assert.Equal(t, []int{http.StatusEarlyHints}, codes)
+	assert.Equal(t, []string{"</style.css>; rel=preload; as=style"}, links)
+	assert.Equal(t, http.StatusOK, res.StatusCode)
+}
+
+func TestEarlyHintsSkippedForHTTP10(t *testing.T) {
+	server, _ := NewServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	req.Proto, req.ProtoMajor, req.ProtoMinor = "HTTP/1.0", 1, 0
+	rec := httptest.NewRecorder()
+	c := server.NewContext(req, rec)
+
+	assert.NoError(t, EarlyHints(c, []string{"</style.css>; rel=preload"}))
+	assert.NoError(t, c.String(http.StatusOK, "test passed"))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, rec.Header().Get("Link"))
+}
@@ -0,0 +1,49 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// errorLog logs handler errors along with the request metadata needed for
+// triage: method, path, request ID, client IP and trace ID
+func (s *Server) errorLog() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			err := next(c)
+			if err == nil {
+				return nil
+			}
+
+			status := http.StatusInternalServerError
+			var he *echo.HTTPError
+			if errors.As(err, &he) {
+				status = he.Code
+			}
+
+			level := slog.ErrorLevel
+			if status < http.StatusInternalServerError {
+				level = slog.WarnLevel
+			}
+
+			req := c.Request()
+			fields := slog.M{
+				"error":      err.Error(),
+				"status":     status,
+				"method":     req.Method,
+				"path":       req.URL.Path,
+				"request_id": logRequestID(c),
+				"client_ip":  c.RealIP(),
+			}
+			if traceID := TraceID(c); len(traceID) > 0 {
+				fields["trace_id"] = traceID
+			}
+			s.log(level, "request error", fields)
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorLog(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithErrorLog())
+	rr := NewRouters()
+	rr.AddRouter("/fail", Methods{
+		http.MethodGet: func(c Context) error {
+			return errors.New("database unavailable")
+		},
+	})
+	rr.AddRouter("/ok", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	req := httptest.NewRequest(http.MethodGet, "/ok", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+
+	req = httptest.NewRequest(http.MethodGet, "/fail", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-123")
+	req.Header.Set(echo.HeaderXRealIP, "203.0.113.7")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.NoError(t, logger.Flush())
+
+	var records []map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]any
+		assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record))
+		records = append(records, record)
+	}
+
+	if assert.Len(t, records, 1) {
+		record := records[0]
+		assert.Equal(t, "request error", record["message"])
+		assert.Equal(t, "ERROR", record["level"])
+		assert.Equal(t, "database unavailable", record["error"])
+		assert.Equal(t, float64(http.StatusInternalServerError), record["status"])
+		assert.Equal(t, http.MethodGet, record["method"])
+		assert.Equal(t, "/fail", record["path"])
+		assert.Equal(t, "req-123", record["request_id"])
+		assert.Equal(t, "203.0.113.7", record["client_ip"])
+	}
+}
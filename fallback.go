@@ -0,0 +1,21 @@
+package server
+
+// WithFallback wraps a route handler so that an error matching shouldFallback
+// is answered by the fallback handler instead of reaching the error handler,
+// e.g. to serve stale data while a dependency is down. A nil shouldFallback
+// falls back on every error. Responses already committed by the route are
+// left untouched.
+func WithFallback(route, fallback HandlerFunc, shouldFallback func(err error) bool) HandlerFunc {
+	return func(c Context) error {
+		err := route(c)
+		if err == nil || c.Response().Committed {
+			return err
+		}
+
+		if shouldFallback != nil && !shouldFallback(err) {
+			return err
+		}
+
+		return fallback(c)
+	}
+}
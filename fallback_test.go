@@ -0,0 +1,66 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+var errDatabaseDown = errors.New("database down")
+
+func TestWithFallback(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+
+	fallback := func(c Context) error {
+		return c.String(http.StatusOK, "stale data")
+	}
+	isDatabaseDown := func(err error) bool {
+		return errors.Is(err, errDatabaseDown)
+	}
+
+	rr.AddRouter("/down", Methods{
+		http.MethodGet: WithFallback(func(c Context) error {
+			return errDatabaseDown
+		}, fallback, isDatabaseDown),
+	})
+	rr.AddRouter("/broken", Methods{
+		http.MethodGet: WithFallback(func(c Context) error {
+			return errors.New("unexpected")
+		}, fallback, isDatabaseDown),
+	})
+	rr.AddRouter("/ok", Methods{
+		http.MethodGet: WithFallback(func(c Context) error {
+			return c.String(http.StatusOK, "fresh data")
+		}, fallback, isDatabaseDown),
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Fallback on matching error", "/down", http.StatusOK, "stale data"},
+		{"No fallback on other errors", "/broken", http.StatusInternalServerError, `{"message":"Internal Server Error"}` + "\n"},
+		{"Primary response when no error", "/ok", http.StatusOK, "fresh data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
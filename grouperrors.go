@@ -0,0 +1,26 @@
+package server
+
+// GroupErrorHandler renders an error raised by a handler of a group. It
+// returns nil once the response is written, or an error to hand over to the
+// server error handler instead.
+type GroupErrorHandler func(err error, c Context) error
+
+// groupErrors returns the error handling middleware scoped to the given
+// group. Echo has a single error handler, so errors are converted here
+// before they reach it.
+func (s *ServerParams) groupErrors(group Kind) []MiddlewareFunc {
+	handler, ok := s.GetGroupErrorHandlers()[group]
+	if !ok {
+		return nil
+	}
+
+	return []MiddlewareFunc{func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			err := next(c)
+			if err == nil || c.Response().Committed {
+				return err
+			}
+			return handler(err, c)
+		}
+	}}
+}
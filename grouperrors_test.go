@@ -0,0 +1,59 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGroupErrorHandler(t *testing.T) {
+	htmlErrors := func(err error, c Context) error {
+		code := http.StatusInternalServerError
+		var he *echo.HTTPError
+		if errors.As(err, &he) {
+			code = he.Code
+		}
+		return c.HTML(code, fmt.Sprintf("<h1>%d</h1><p>%s</p>", code, html.EscapeString(http.StatusText(code))))
+	}
+
+	server, err := NewServer(WithGroupErrorHandler(DOCS, htmlErrors))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/page", Methods{
+		http.MethodGet: func(c Context) error {
+			return echo.ErrForbidden
+		},
+	})
+	_ = server.RegisterRouters(DOCS, rr)
+	_ = server.RegisterRouters(API, rr)
+
+	tests := []struct {
+		name        string
+		path        string
+		status      int
+		contentType string
+		body        string
+	}{
+		{"Docs error", "/docs/page", http.StatusForbidden, echo.MIMETextHTMLCharsetUTF8, "<h1>403</h1><p>Forbidden</p>"},
+		{"Docs not found", "/docs/missing", http.StatusNotFound, echo.MIMETextHTMLCharsetUTF8, "<h1>404</h1><p>Not Found</p>"},
+		{"API error", "/api/page", http.StatusForbidden, echo.MIMEApplicationJSON, `{"message":"Forbidden"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.Equal(t, tt.contentType, rec.Header().Get(echo.HeaderContentType))
+			assert.Equal(t, tt.body, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"github.com/improbable-eng/grpc-web/go/grpcweb"
+	"google.golang.org/grpc"
+)
+
+// grpcWeb serves gRPC-Web requests with the wrapped gRPC server, leaving
+// every other request to echo's router
+func grpcWeb(server *grpc.Server) MiddlewareFunc {
+	wrapped := grpcweb.WrapServer(server)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			req := c.Request()
+			if wrapped.IsGrpcWebRequest(req) || wrapped.IsAcceptableGrpcCorsRequest(req) {
+				wrapped.ServeHTTP(c.Response(), req)
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
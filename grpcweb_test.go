@@ -0,0 +1,81 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/protobuf/proto"
+)
+
+func grpcWebFrame(t *testing.T, msg proto.Message) []byte {
+	payload, err := proto.Marshal(msg)
+	assert.NoError(t, err)
+
+	frame := make([]byte, 5, 5+len(payload))
+	binary.BigEndian.PutUint32(frame[1:], uint32(len(payload)))
+	return append(frame, payload...)
+}
+
+func TestGRPCWeb(t *testing.T) {
+	gs := grpc.NewServer()
+	healthpb.RegisterHealthServer(gs, health.NewServer())
+
+	server, err := NewServer(WithGRPCWeb(gs))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	body := grpcWebFrame(t, &healthpb.HealthCheckRequest{})
+	req := httptest.NewRequest(http.MethodPost, "/grpc.health.v1.Health/Check", bytes.NewReader(body))
+	req.Header.Set("Content-Type", "application/grpc-web+proto")
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	res, err := io.ReadAll(rec.Body)
+	assert.NoError(t, err)
+	if assert.GreaterOrEqual(t, len(res), 5) {
+		assert.Equal(t, byte(0), res[0])
+		size := binary.BigEndian.Uint32(res[1:5])
+
+		msg := &healthpb.HealthCheckResponse{}
+		assert.NoError(t, proto.Unmarshal(res[5:5+size], msg))
+		assert.Equal(t, healthpb.HealthCheckResponse_SERVING, msg.GetStatus())
+
+		trailer := res[5+size:]
+		if assert.Greater(t, len(trailer), 5) {
+			assert.Equal(t, byte(0x80), trailer[0])
+			assert.Contains(t, string(trailer[5:]), "grpc-status: 0")
+		}
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "test passed", rec.Body.String())
+}
+
+func TestWithGRPCWebNil(t *testing.T) {
+	_, err := NewServer(WithGRPCWeb(nil))
+	assert.Error(t, err)
+}
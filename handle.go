@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Sentinel errors a TypedFunc can return, possibly wrapped, to answer with
+// the matching status instead of a 500
+var (
+	ErrBadRequest   = errors.New("bad request")
+	ErrUnauthorized = errors.New("unauthorized")
+	ErrForbidden    = errors.New("forbidden")
+	ErrNotFound     = errors.New("not found")
+	ErrConflict     = errors.New("conflict")
+)
+
+// sentinelStatus maps the sentinel errors to their status codes
+var sentinelStatus = []struct {
+	err    error
+	status int
+}{
+	{ErrBadRequest, http.StatusBadRequest},
+	{ErrUnauthorized, http.StatusUnauthorized},
+	{ErrForbidden, http.StatusForbidden},
+	{ErrNotFound, http.StatusNotFound},
+	{ErrConflict, http.StatusConflict},
+}
+
+// TypedFunc is a strongly typed handler receiving the bound request value
+type TypedFunc[Req, Res any] func(ctx context.Context, req Req) (Res, error)
+
+// Handle registers a typed handler for the given path and method. The
+// request is bound into Req (path params, query and body) and validated as
+// in BindValidate, and the returned Res is encoded as JSON with a 200
+// status. Errors wrapping a sentinel such as ErrNotFound are answered with
+// its status and the error text.
+func Handle[Req, Res any](rr *RegisterRouters, path, method string, fn TypedFunc[Req, Res]) error {
+	return rr.AddRouter(path, Methods{method: typedHandler(fn)})
+}
+
+// GETJSON registers a typed GET handler
+func GETJSON[Req, Res any](rr *RegisterRouters, path string, fn TypedFunc[Req, Res]) error {
+	return Handle(rr, path, http.MethodGet, fn)
+}
+
+// POSTJSON registers a typed POST handler
+func POSTJSON[Req, Res any](rr *RegisterRouters, path string, fn TypedFunc[Req, Res]) error {
+	return Handle(rr, path, http.MethodPost, fn)
+}
+
+// PUTJSON registers a typed PUT handler
+func PUTJSON[Req, Res any](rr *RegisterRouters, path string, fn TypedFunc[Req, Res]) error {
+	return Handle(rr, path, http.MethodPut, fn)
+}
+
+// PATCHJSON registers a typed PATCH handler
+func PATCHJSON[Req, Res any](rr *RegisterRouters, path string, fn TypedFunc[Req, Res]) error {
+	return Handle(rr, path, http.MethodPatch, fn)
+}
+
+// DELETEJSON registers a typed DELETE handler
+func DELETEJSON[Req, Res any](rr *RegisterRouters, path string, fn TypedFunc[Req, Res]) error {
+	return Handle(rr, path, http.MethodDelete, fn)
+}
+
+// typedHandler adapts a TypedFunc to an echo handler
+func typedHandler[Req, Res any](fn TypedFunc[Req, Res]) HandlerFunc {
+	return func(c Context) error {
+		req := new(Req)
+		if err := BindValidate(c, req); err != nil {
+			return err
+		}
+
+		res, err := fn(c.Request().Context(), *req)
+		if err != nil {
+			return sentinelError(err)
+		}
+
+		return c.JSON(http.StatusOK, res)
+	}
+}
+
+// sentinelError turns errors wrapping a sentinel into an HTTP error with its
+// status, leaving any other error to the server error handler
+func sentinelError(err error) error {
+	for _, s := range sentinelStatus {
+		if errors.Is(err, s.err) {
+			return echo.NewHTTPError(s.status, err.Error()).SetInternal(err)
+		}
+	}
+	return err
+}
+
+// ResponderFunc is a handler returning the status and body to send as JSON
+type ResponderFunc func(c Context) (int, any, error)
+
+// ToHandler adapts a ResponderFunc to an echo handler. Errors are left to
+// the server error handler and a nil body sends no content.
+func ToHandler(fn ResponderFunc) HandlerFunc {
+	return func(c Context) error {
+		status, body, err := fn(c)
+		if err != nil {
+			return err
+		}
+
+		if body == nil {
+			return c.NoContent(status)
+		}
+
+		return c.JSON(status, body)
+	}
+}
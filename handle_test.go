@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type greetRequest struct {
+	ID   string `param:"id"`
+	Name string `json:"name" query:"name"`
+}
+
+type greetResponse struct {
+	Message string `json:"message"`
+}
+
+func TestTypedHandlers(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+
+	POSTJSON(rr, "/greet/:id", func(ctx context.Context, req greetRequest) (greetResponse, error) {
+		return greetResponse{Message: req.ID + ":" + req.Name}, nil
+	})
+	GETJSON(rr, "/greet", func(ctx context.Context, req greetRequest) (greetResponse, error) {
+		return greetResponse{Message: "hello " + req.Name}, nil
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedBody string
+	}{
+		{
+			name:         "POST binds param and body",
+			method:       http.MethodPost,
+			path:         "/greet/42",
+			body:         `{"name":"gopher"}`,
+			expectedBody: `{"message":"42:gopher"}`,
+		},
+		{
+			name:         "GET binds query",
+			method:       http.MethodGet,
+			path:         "/greet?name=gopher",
+			expectedBody: `{"message":"hello gopher"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestTypedHandlerError(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+
+	DELETEJSON(rr, "/greet/:id", func(ctx context.Context, req greetRequest) (greetResponse, error) {
+		return greetResponse{}, errors.New("boom")
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+	req := httptest.NewRequest(http.MethodDelete, "/greet/1", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestTypedHandlerBadBody(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+
+	PUTJSON(rr, "/greet/:id", func(ctx context.Context, req greetRequest) (greetResponse, error) {
+		return greetResponse{}, nil
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+	req := httptest.NewRequest(http.MethodPut, "/greet/1", strings.NewReader(`{"name":`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestToHandler(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/items/:id", Methods{
+		http.MethodPost: ToHandler(func(c Context) (int, any, error) {
+			return http.StatusCreated, map[string]string{"id": c.Param("id")}, nil
+		}),
+		http.MethodDelete: ToHandler(func(c Context) (int, any, error) {
+			return http.StatusNoContent, nil, nil
+		}),
+		http.MethodGet: ToHandler(func(c Context) (int, any, error) {
+			return 0, nil, echo.NewHTTPError(http.StatusNotFound, "item not found")
+		}),
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name         string
+		method       string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Status and body", http.MethodPost, http.StatusCreated, `{"id":"7"}`},
+		{"No body", http.MethodDelete, http.StatusNoContent, ""},
+		{"Error", http.MethodGet, http.StatusNotFound, `{"message":"item not found"}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/items/7", nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedBody == "" {
+				assert.Empty(t, rec.Body.String())
+			} else {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestHandleDuplicate(t *testing.T) {
+	rr := NewRouters()
+	fn := func(ctx context.Context, req struct{}) (string, error) { return "ok", nil }
+
+	assert.NoError(t, GETJSON(rr, "/items", fn))
+	assert.NoError(t, POSTJSON(rr, "/items", fn))
+	assert.Error(t, GETJSON(rr, "/items", fn))
+}
+
+type createItemRequest struct {
+	Name string `json:"name" validate:"required"`
+}
+
+func TestTypedHandlerSentinelErrors(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+
+	GETJSON(rr, "/items/:id", func(ctx context.Context, req greetRequest) (greetResponse, error) {
+		switch req.ID {
+		case "missing":
+			return greetResponse{}, fmt.Errorf("item %s: %w", req.ID, ErrNotFound)
+		case "bad":
+			return greetResponse{}, ErrBadRequest
+		case "taken":
+			return greetResponse{}, fmt.Errorf("item %s: %w", req.ID, ErrConflict)
+		}
+		return greetResponse{Message: req.ID}, nil
+	})
+	POSTJSON(rr, "/items", func(ctx context.Context, req createItemRequest) (greetResponse, error) {
+		return greetResponse{Message: req.Name}, nil
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"found", http.MethodGet, "/items/7", "", http.StatusOK, `{"message":"7"}`},
+		{"not found", http.MethodGet, "/items/missing", "", http.StatusNotFound, `{"message":"item missing: not found"}`},
+		{"bad request", http.MethodGet, "/items/bad", "", http.StatusBadRequest, `{"message":"bad request"}`},
+		{"conflict", http.MethodGet, "/items/taken", "", http.StatusConflict, `{"message":"item taken: conflict"}`},
+		{"validated", http.MethodPost, "/items", `{}`, http.StatusBadRequest,
+			`{"message":"validation failed","fields":[{"field":"name","message":"is required"}]}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultLivenessPath  = "/healthz"
+	defaultReadinessPath = "/readyz"
+)
+
+// HealthProbe checks a dependency, e.g. a database ping, returning an error
+// when it is unavailable
+type HealthProbe func(ctx context.Context) error
+
+// HealthOptions configures the endpoints added by RegisterHealthChecks
+type HealthOptions struct {
+	// LivenessPath defaults to /healthz
+	LivenessPath string
+
+	// ReadinessPath defaults to /readyz
+	ReadinessPath string
+
+	// Probes are run concurrently on every readiness request, keyed by the
+	// name reported when they fail
+	Probes map[string]HealthProbe
+
+	// Timeout bounds each readiness request, no limit when zero
+	Timeout time.Duration
+}
+
+// HealthStatus is the JSON body returned by the health endpoints
+type HealthStatus struct {
+	Status  string            `json:"status"`
+	Failing map[string]string `json:"failing,omitempty"`
+}
+
+// RegisterHealthChecks registers a liveness endpoint always answering 200
+// and a readiness endpoint answering 503 with the failing probes listed.
+// Readiness also fails while IsReady is false, that is before Start, until
+// the start hooks complete and MarkReady is called, and while the server is
+// shutting down, so load balancers only send traffic to a ready server.
+func (s *Server) RegisterHealthChecks(opts HealthOptions) error {
+	liveness := opts.LivenessPath
+	if len(liveness) == 0 {
+		liveness = defaultLivenessPath
+	}
+	readiness := opts.ReadinessPath
+	if len(readiness) == 0 {
+		readiness = defaultReadinessPath
+	}
+
+	rr := NewRouters()
+	if err := rr.AddRoute(RegisterRouter{
+		Path: liveness,
+		Methods: Methods{http.MethodGet: func(c Context) error {
+			return c.JSON(http.StatusOK, HealthStatus{Status: "ok"})
+		}},
+		Summary: "Liveness check",
+		builtin: true,
+	}); err != nil {
+		return err
+	}
+	if err := rr.AddRoute(RegisterRouter{
+		Path:    readiness,
+		Methods: Methods{http.MethodGet: s.readiness(opts)},
+		Summary: "Readiness check",
+		builtin: true,
+	}); err != nil {
+		return err
+	}
+
+	return s.RegisterRouters(ROOT, rr)
+}
+
+// readiness runs the probes and reports the failing ones
+func (s *Server) readiness(opts HealthOptions) HandlerFunc {
+	return func(c Context) error {
+		ctx := c.Request().Context()
+		if opts.Timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+			defer cancel()
+		}
+
+		failing := map[string]string{}
+		if !s.IsReady() {
+			switch {
+			case s.draining.Load():
+				failing["server"] = "shutting down"
+			case !s.Started():
+				failing["server"] = "not started"
+			default:
+				failing["server"] = "not ready"
+			}
+		}
+
+		var (
+			mu sync.Mutex
+			wg sync.WaitGroup
+		)
+		for name, probe := range opts.Probes {
+			wg.Add(1)
+			go func(name string, probe HealthProbe) {
+				defer wg.Done()
+				if err := probe(ctx); err != nil {
+					mu.Lock()
+					failing[name] = err.Error()
+					mu.Unlock()
+				}
+			}(name, probe)
+		}
+		wg.Wait()
+
+		if len(failing) > 0 {
+			return c.JSON(http.StatusServiceUnavailable, HealthStatus{Status: "unavailable", Failing: failing})
+		}
+
+		return c.JSON(http.StatusOK, HealthStatus{Status: "ok"})
+	}
+}
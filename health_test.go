@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterHealthChecks(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	dbErr := error(nil)
+	assert.NoError(t, server.RegisterHealthChecks(HealthOptions{
+		Probes: map[string]HealthProbe{
+			"database": func(ctx context.Context) error { return dbErr },
+			"cache":    func(ctx context.Context) error { return nil },
+		},
+	}))
+
+	get := func(path string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec
+	}
+
+	rec := get("/healthz")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	rec = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"unavailable","failing":{"server":"not started"}}`, rec.Body.String())
+
+	server.Start()
+	<-server.Listening()
+	defer server.Close()
+
+	rec = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"unavailable","failing":{"server":"not ready"}}`, rec.Body.String())
+
+	server.MarkReady()
+	assert.Eventually(t, server.IsReady, time.Second, 10*time.Millisecond)
+
+	rec = get("/readyz")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"status":"ok"}`, rec.Body.String())
+
+	dbErr = errors.New("connection refused")
+	rec = get("/readyz")
+	assert.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	assert.JSONEq(t, `{"status":"unavailable","failing":{"database":"connection refused"}}`, rec.Body.String())
+
+	// liveness is unaffected by failing probes
+	assert.Equal(t, http.StatusOK, get("/healthz").Code)
+}
+
+func TestRegisterHealthChecksCustomPaths(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+	assert.NoError(t, server.RegisterHealthChecks(HealthOptions{
+		LivenessPath:  "/live",
+		ReadinessPath: "/ready",
+	}))
+
+	server.Start()
+	<-server.Listening()
+	defer server.Close()
+
+	server.MarkReady()
+	assert.Eventually(t, server.IsReady, time.Second, 10*time.Millisecond)
+
+	for path, status := range map[string]int{
+		"/live":    http.StatusOK,
+		"/ready":   http.StatusOK,
+		"/healthz": http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, status, rec.Code, path)
+	}
+
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"sync/atomic"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HeaderHedgeKey identifies the copies of a request a client hedged, i.e.
+// sent several times to take the first answer
+const HeaderHedgeKey = "X-Hedge-Key"
+
+// hedgeRequest is an in-flight request carrying a hedge key
+type hedgeRequest struct {
+	cancel     context.CancelFunc
+	superseded atomic.Bool
+}
+
+// hedger tracks in-flight requests by hedge key so the slower copies can be
+// cancelled once one of them succeeds. Keys are scoped with hedgeScope so a
+// client can't cancel another client's requests by reusing its key.
+type hedger struct {
+	header string
+
+	mu       sync.Mutex
+	inFlight map[string]map[*hedgeRequest]struct{}
+
+	count     atomic.Uint64
+	cancelled prometheus.Counter
+}
+
+func newHedger(header string) *hedger {
+	if len(header) == 0 {
+		header = HeaderHedgeKey
+	}
+	return &hedger{
+		header:   header,
+		inFlight: make(map[string]map[*hedgeRequest]struct{}),
+	}
+}
+
+func (h *hedger) join(key string, r *hedgeRequest) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.inFlight[key] == nil {
+		h.inFlight[key] = make(map[*hedgeRequest]struct{})
+	}
+	h.inFlight[key][r] = struct{}{}
+}
+
+// finish removes r, and when it succeeded cancels the other copies still
+// running, returning how many were cancelled
+func (h *hedger) finish(key string, r *hedgeRequest, succeeded bool) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	copies := h.inFlight[key]
+	delete(copies, r)
+
+	cancelled := 0
+	if succeeded && !r.superseded.Load() {
+		for other := range copies {
+			other.superseded.Store(true)
+			other.cancel()
+			cancelled++
+		}
+		clear(copies)
+	}
+	if len(copies) == 0 {
+		delete(h.inFlight, key)
+	}
+
+	return cancelled
+}
+
+// hedgeScope qualifies the client supplied key with the client IP and the
+// request method and path, which the copies of a hedged request share
+func hedgeScope(c Context, key string) string {
+	req := c.Request()
+	return c.RealIP() + " " + req.Method + " " + req.URL.Path + " " + key
+}
+
+// cancelHedged cancels the context of the hedged copies of a request once
+// one of them completes without error, so the server stops working on an
+// answer the client no longer waits for. A cancelled copy that has not
+// responded yet gets a 409.
+func (s *Server) cancelHedged() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			key := c.Request().Header.Get(s.hedges.header)
+			if len(key) == 0 {
+				return next(c)
+			}
+			scope := hedgeScope(c, key)
+
+			ctx, cancel := context.WithCancel(c.Request().Context())
+			defer cancel()
+
+			r := &hedgeRequest{cancel: cancel}
+			s.hedges.join(scope, r)
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			err := next(c)
+
+			succeeded := err == nil && c.Response().Status < http.StatusInternalServerError
+			if n := s.hedges.finish(scope, r, succeeded); n > 0 {
+				s.hedges.count.Add(uint64(n))
+				if s.hedges.cancelled != nil {
+					s.hedges.cancelled.Add(float64(n))
+				}
+				s.log(slog.DebugLevel, "hedged requests cancelled", slog.M{
+					"key":       key,
+					"cancelled": n,
+					"path":      c.Request().URL.Path,
+				})
+			}
+
+			if r.superseded.Load() && !c.Response().Committed {
+				return echo.NewHTTPError(http.StatusConflict, "superseded by a hedged request")
+			}
+			return err
+		}
+	}
+}
+
+// HedgeCancellations returns how many hedged requests were cancelled. It is
+// always zero unless the server was created with WithHedging.
+func (s *Server) HedgeCancellations() uint64 {
+	if s.hedges == nil {
+		return 0
+	}
+	return s.hedges.count.Load()
+}
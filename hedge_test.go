@@ -0,0 +1,153 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHedging(t *testing.T) {
+	server, err := NewServer(WithMetrics("shop"), WithHedging(""))
+	assert.NoError(t, err)
+	assert.Contains(t, server.Middlewares(), "hedging")
+
+	started := make(chan struct{})
+	cancelled := make(chan struct{})
+	rr := NewRouters()
+	rr.AddRouter("/quote", Methods{
+		http.MethodGet: func(c Context) error {
+			if c.QueryParam("slow") == "" {
+				<-started
+				return c.String(http.StatusOK, "fast")
+			}
+
+			close(started)
+			select {
+			case <-c.Request().Context().Done():
+				close(cancelled)
+				return c.Request().Context().Err()
+			case <-time.After(2 * time.Second):
+				return c.String(http.StatusOK, "slow")
+			}
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+	_ = server.RegisterMetricsEndpoint("/metrics")
+
+	send := func(path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(HeaderHedgeKey, "quote-1")
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	var (
+		wg   sync.WaitGroup
+		slow *httptest.ResponseRecorder
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		slow = send("/quote?slow=1")
+	}()
+
+	fast := send("/quote")
+	wg.Wait()
+
+	assert.Equal(t, http.StatusOK, fast.Code)
+	assert.Equal(t, "fast", fast.Body.String())
+
+	select {
+	case <-cancelled:
+	default:
+		t.Fatal("slow hedged request was not cancelled")
+	}
+	assert.Equal(t, http.StatusConflict, slow.Code)
+	assert.Equal(t, uint64(1), server.HedgeCancellations())
+
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), "shop_http_hedge_cancellations_total 1")
+}
+
+func TestHedgingScopedByClient(t *testing.T) {
+	server, _ := NewServer(WithHedging(""))
+
+	started := make(chan struct{})
+	release := make(chan struct{})
+	rr := NewRouters()
+	rr.AddRouter("/quote", Methods{
+		http.MethodGet: func(c Context) error {
+			if c.QueryParam("slow") == "" {
+				return c.String(http.StatusOK, "fast")
+			}
+
+			close(started)
+			select {
+			case <-c.Request().Context().Done():
+				return c.Request().Context().Err()
+			case <-release:
+				return c.String(http.StatusOK, "slow")
+			}
+		},
+	})
+	rr.AddRouter("/other", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "other")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	send := func(path, remote string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.RemoteAddr = remote
+		req.Header.Set(HeaderHedgeKey, "quote-1")
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec
+	}
+
+	done := make(chan *httptest.ResponseRecorder)
+	go func() { done <- send("/quote?slow=1", "10.0.0.1:1234") }()
+	<-started
+
+	// same key from another client, or on another route, cancels nothing
+	assert.Equal(t, http.StatusOK, send("/quote", "10.0.0.2:1234").Code)
+	assert.Equal(t, http.StatusOK, send("/other", "10.0.0.1:1234").Code)
+	close(release)
+
+	slow := <-done
+	assert.Equal(t, http.StatusOK, slow.Code)
+	assert.Equal(t, "slow", slow.Body.String())
+	assert.Zero(t, server.HedgeCancellations())
+}
+
+func TestHedgingDistinctKeys(t *testing.T) {
+	server, _ := NewServer(WithHedging("X-Request-Group"))
+
+	rr := NewRouters()
+	rr.AddRouter("/quote", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "ok")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	for _, key := range []string{"a", "b", "a", ""} {
+		req := httptest.NewRequest(http.MethodGet, "/quote", nil)
+		req.Header.Set("X-Request-Group", key)
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusOK, rec.Code)
+	}
+
+	assert.Zero(t, server.HedgeCancellations())
+
+	plain, _ := NewServer()
+	assert.Zero(t, plain.HedgeCancellations())
+}
@@ -0,0 +1,93 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// writeErrorWriter remembers the first error returned by the underlying
+// writer, usually a client that went away
+type writeErrorWriter struct {
+	http.ResponseWriter
+	err error
+}
+
+func (w *writeErrorWriter) Write(b []byte) (int, error) {
+	n, err := w.ResponseWriter.Write(b)
+	if err != nil && w.err == nil {
+		w.err = err
+	}
+	return n, err
+}
+
+func (w *writeErrorWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		f.Flush()
+	}
+}
+
+func (w *writeErrorWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// trackIncomplete counts responses that were not fully delivered, either
+// because a write failed or fewer bytes than the Content-Length were sent.
+// The Content-Length of bodiless responses such as HEAD is not checked.
+func (s *Server) trackIncomplete() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			res := c.Response()
+			w := &writeErrorWriter{ResponseWriter: res.Writer}
+			res.Writer = w
+
+			defer func() {
+				res.Writer = w.ResponseWriter
+
+				expected := int64(-1)
+				if cl := res.Header().Get(echo.HeaderContentLength); len(cl) > 0 && hasBody(c.Request().Method, res.Status) {
+					if n, err := strconv.ParseInt(cl, 10, 64); err == nil {
+						expected = n
+					}
+				}
+
+				if w.err == nil && (expected < 0 || res.Size >= expected) {
+					return
+				}
+
+				s.incomplete.Add(1)
+
+				fields := slog.M{
+					"method":   c.Request().Method,
+					"path":     c.Request().URL.Path,
+					"written":  res.Size,
+					"expected": expected,
+				}
+				if w.err != nil {
+					fields["error"] = w.err.Error()
+				}
+				s.log(slog.DebugLevel, "incomplete response", fields)
+			}()
+
+			return next(c)
+		}
+	}
+}
+
+// hasBody reports whether a response may carry the body its Content-Length
+// announces; HEAD responses and 1xx, 204 and 304 statuses never do
+func hasBody(method string, status int) bool {
+	if method == http.MethodHead {
+		return false
+	}
+	return status >= http.StatusOK && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// IncompleteResponses returns how many responses were only partially
+// delivered. It is always zero unless the server was created with
+// WithIncompleteResponses.
+func (s *Server) IncompleteResponses() uint64 {
+	return s.incomplete.Load()
+}
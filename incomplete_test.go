@@ -0,0 +1,93 @@
+package server
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+// shortWriter accepts up to limit bytes and then fails like a client that
+// disconnected mid-response
+type shortWriter struct {
+	*httptest.ResponseRecorder
+	limit int
+}
+
+func (w *shortWriter) Write(b []byte) (int, error) {
+	if w.Body.Len()+len(b) <= w.limit {
+		return w.ResponseRecorder.Write(b)
+	}
+	n, _ := w.ResponseRecorder.Write(b[:w.limit-w.Body.Len()])
+	return n, errors.New("broken pipe")
+}
+
+func TestIncompleteResponses(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithIncompleteResponses())
+	rr := NewRouters()
+	rr.AddRouter("/large", Methods{
+		http.MethodGet: func(c Context) error {
+			body := bytes.Repeat([]byte("x"), 100)
+			c.Response().Header().Set("Content-Length", strconv.Itoa(len(body)))
+			return c.Blob(http.StatusOK, "text/plain", body)
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	req := httptest.NewRequest(http.MethodGet, "/large", nil)
+	e.ServeHTTP(httptest.NewRecorder(), req)
+	assert.Equal(t, uint64(0), server.IncompleteResponses())
+
+	req = httptest.NewRequest(http.MethodGet, "/large", nil)
+	e.ServeHTTP(&shortWriter{ResponseRecorder: httptest.NewRecorder(), limit: 40}, req)
+	assert.Equal(t, uint64(1), server.IncompleteResponses())
+
+	assert.NoError(t, logger.Flush())
+	assert.Contains(t, buf.String(), "incomplete response")
+	assert.Contains(t, buf.String(), `"written":40`)
+	assert.Contains(t, buf.String(), `"expected":100`)
+	assert.Contains(t, buf.String(), `"error":"broken pipe"`)
+}
+
+func TestIncompleteResponsesBodiless(t *testing.T) {
+	server, _ := NewServer(WithIncompleteResponses())
+
+	withLength := func(status int) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("Content-Length", "100")
+			return c.NoContent(status)
+		}
+	}
+	rr := NewRouters()
+	rr.AddRouter("/large", Methods{http.MethodHead: withLength(http.StatusOK)})
+	rr.AddRouter("/cached", Methods{http.MethodGet: withLength(http.StatusNotModified)})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodHead, "/large", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/cached", nil))
+	assert.Equal(t, http.StatusNotModified, rec.Code)
+
+	assert.Equal(t, uint64(0), server.IncompleteResponses())
+}
+
+func TestIncompleteResponsesDisabled(t *testing.T) {
+	server, _ := NewServer()
+	assert.Equal(t, uint64(0), server.IncompleteResponses())
+}
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// jsonSerializer is echo's JSON serializer with bind errors that tell the
+// client what is wrong with the body and where. With useNumber, numbers
+// decoded into interface values are json.Number instead of float64, so
+// large integers keep their precision.
+type jsonSerializer struct {
+	echo.DefaultJSONSerializer
+	useNumber bool
+}
+
+func (s jsonSerializer) Deserialize(c Context, i any) error {
+	dec := json.NewDecoder(c.Request().Body)
+	if s.useNumber {
+		dec.UseNumber()
+	}
+
+	err := dec.Decode(i)
+	if err == nil {
+		return nil
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		msg       string
+	)
+
+	switch {
+	case errors.As(err, &syntaxErr):
+		msg = fmt.Sprintf("malformed JSON at offset %d: %s", syntaxErr.Offset, syntaxErr.Error())
+	case errors.As(err, &typeErr) && len(typeErr.Field) > 0:
+		msg = fmt.Sprintf("invalid JSON: field %q must be %s, got %s (offset %d)",
+			typeErr.Field, typeErr.Type, typeErr.Value, typeErr.Offset)
+	case errors.As(err, &typeErr):
+		msg = fmt.Sprintf("invalid JSON: expected %s, got %s (offset %d)",
+			typeErr.Type, typeErr.Value, typeErr.Offset)
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		msg = "malformed JSON: unexpected end of input"
+	default:
+		return err
+	}
+
+	return echo.NewHTTPError(http.StatusBadRequest, msg).SetInternal(err)
+}
@@ -0,0 +1,162 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMalformedJSON(t *testing.T) {
+	server, _ := NewServer()
+
+	type user struct {
+		Name    string `json:"name"`
+		Age     int    `json:"age"`
+		Address struct {
+			Zip int `json:"zip"`
+		} `json:"address"`
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodPost: func(c Context) error {
+			var u user
+			if err := c.Bind(&u); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, u)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name            string
+		body            string
+		expectedCode    int
+		expectedMessage string
+	}{
+		{"Valid body", `{"name":"Ada","age":36}`, http.StatusOK, ""},
+		{"Syntax error", `{"name":"Ada",}`, http.StatusBadRequest, "malformed JSON at offset 15: invalid character '}' looking for beginning of object key string"},
+		{"Wrong field type", `{"name":"Ada","age":"old"}`, http.StatusBadRequest, `invalid JSON: field "age" must be int, got string (offset 25)`},
+		{"Wrong nested field type", `{"address":{"zip":"none"}}`, http.StatusBadRequest, `invalid JSON: field "address.zip" must be int, got string (offset 24)`},
+		{"Wrong body type", `["Ada"]`, http.StatusBadRequest, "invalid JSON: expected server.user, got array (offset 1)"},
+		{"Truncated body", `{"name":"Ada"`, http.StatusBadRequest, "malformed JSON: unexpected end of input"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if len(tt.expectedMessage) > 0 {
+				var body map[string]string
+				assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+				assert.Equal(t, tt.expectedMessage, body["message"])
+			}
+		})
+	}
+}
+
+// wrappingSerializer is a stand-in for an alternative JSON library
+type wrappingSerializer struct {
+	echo.DefaultJSONSerializer
+	calls *int
+}
+
+func (u wrappingSerializer) Serialize(c Context, i any, indent string) error {
+	*u.calls++
+	return u.DefaultJSONSerializer.Serialize(c, map[string]any{"wrapped": i}, indent)
+}
+
+func TestWithJSONSerializer(t *testing.T) {
+	calls := 0
+	server, err := NewServer(WithJSONSerializer(wrappingSerializer{calls: &calls}))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodPost: func(c Context) error {
+			var u map[string]any
+			if err := c.Bind(&u); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusOK, u)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"name":"gopher"}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `{"wrapped":{"name":"gopher"}}`, rec.Body.String())
+	assert.Equal(t, 1, calls)
+
+	_, err = NewServer(WithJSONSerializer(nil))
+	assert.Error(t, err)
+
+	// the custom serializer would silently drop UseNumber
+	_, err = NewServer(WithJSONUseNumber(), WithJSONSerializer(wrappingSerializer{calls: &calls}))
+	assert.Error(t, err)
+	_, err = NewServer(WithJSONSerializer(wrappingSerializer{calls: &calls}), WithJSONUseNumber())
+	assert.Error(t, err)
+}
+
+func TestWithJSONUseNumber(t *testing.T) {
+	const body = `{"id":9007199254740993}`
+
+	tests := []struct {
+		name     string
+		opts     []Options
+		expected string
+	}{
+		{"use number", []Options{WithJSONUseNumber()}, body},
+		{"float64", nil, `{"id":9007199254740992}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := NewServer(tt.opts...)
+			rr := NewRouters()
+			rr.AddRouter("/bind", Methods{
+				http.MethodPost: func(c Context) error {
+					var v map[string]any
+					if err := c.Bind(&v); err != nil {
+						return err
+					}
+					return c.JSON(http.StatusOK, v)
+				},
+			})
+			rr.AddRouter("/negotiate", Methods{
+				http.MethodPost: func(c Context) error {
+					var v map[string]any
+					if err := BindByContentType(c, &v); err != nil {
+						return err
+					}
+					return c.JSON(http.StatusOK, v)
+				},
+			})
+			_ = server.RegisterRouters(ROOT, rr)
+
+			for _, path := range []string{"/bind", "/negotiate"} {
+				req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+				req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+				rec := httptest.NewRecorder()
+				server.GetEcho().ServeHTTP(rec, req)
+
+				assert.Equal(t, http.StatusOK, rec.Code, path)
+				assert.Equal(t, tt.expected, strings.TrimSpace(rec.Body.String()), path)
+			}
+		})
+	}
+}
@@ -0,0 +1,37 @@
+package server
+
+import (
+	"runtime"
+
+	"github.com/gookit/slog"
+)
+
+// defaultGoroutineLeakThreshold is how many goroutines a request may leave
+// behind before it is reported
+const defaultGoroutineLeakThreshold = 5
+
+// detectGoroutineLeaks compares the goroutine count before and after each
+// request and warns when it grew past threshold. The count is process-wide,
+// so concurrent requests add noise: this is a debugging aid, not an exact
+// measurement.
+func (s *Server) detectGoroutineLeaks(threshold int) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			before := runtime.NumGoroutine()
+			err := next(c)
+			after := runtime.NumGoroutine()
+
+			if after-before > threshold {
+				s.log(slog.WarnLevel, "goroutine leak suspected", slog.M{
+					"method": c.Request().Method,
+					"path":   c.Request().URL.Path,
+					"route":  c.Path(),
+					"before": before,
+					"after":  after,
+				})
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,66 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoroutineLeakDetection(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithGoroutineLeakDetection())
+	assert.Contains(t, server.Middlewares(), "goroutine-leak-detection")
+
+	release := make(chan struct{})
+	defer close(release)
+
+	rr := NewRouters()
+	_ = rr.AddRouter("/leak", Methods{
+		http.MethodGet: func(c Context) error {
+			for i := 0; i < 2*defaultGoroutineLeakThreshold; i++ {
+				go func() { <-release }()
+			}
+			return c.NoContent(http.StatusOK)
+		},
+	})
+	_ = rr.AddRouter("/clean", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.NoContent(http.StatusOK)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	serve := func(path string) []map[string]any {
+		buf.Reset()
+		server.GetEcho().ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+		assert.NoError(t, logger.Flush())
+
+		var records []map[string]any
+		scanner := bufio.NewScanner(&buf)
+		for scanner.Scan() {
+			var record map[string]any
+			if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record)) {
+				records = append(records, record)
+			}
+		}
+		return records
+	}
+
+	assert.Empty(t, serve("/clean"))
+
+	records := serve("/leak")
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "goroutine leak suspected", records[0]["message"])
+		assert.Equal(t, "WARN", records[0]["level"])
+		assert.Equal(t, "/leak", records[0]["route"])
+		assert.GreaterOrEqual(t, records[0]["after"].(float64)-records[0]["before"].(float64), float64(2*defaultGoroutineLeakThreshold))
+	}
+}
@@ -0,0 +1,30 @@
+package server
+
+import (
+	"fmt"
+
+	"github.com/gookit/slog"
+)
+
+// log writes a structured record to the configured Slog, falling back to
+// echo's logger when none is set
+func (s *Server) log(level slog.Level, msg string, fields slog.M) {
+	if l := s.params.GetSlog(); l != nil {
+		l.WithFields(fields).Log(level, msg)
+		return
+	}
+
+	line := msg
+	if len(fields) > 0 {
+		line = fmt.Sprintf("%s %v", msg, fields)
+	}
+
+	switch {
+	case level <= slog.ErrorLevel:
+		s.front.Logger.Error(line)
+	case level <= slog.WarnLevel:
+		s.front.Logger.Warn(line)
+	default:
+		s.front.Logger.Info(line)
+	}
+}
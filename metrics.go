@@ -0,0 +1,130 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// metricNamespace matches the names prometheus accepts as a namespace
+var metricNamespace = regexp.MustCompile(`^[a-zA-Z_:][a-zA-Z0-9_:]*$`)
+
+// metrics holds the request metrics installed by WithMetrics. Each server
+// has its own registry so several servers in one process do not clash.
+type metrics struct {
+	namespace string
+	registry  *prometheus.Registry
+	requests  *prometheus.CounterVec
+	duration  *prometheus.HistogramVec
+	inFlight  *prometheus.GaugeVec
+}
+
+// newMetrics creates the request metrics along with the Go and process
+// collectors
+func newMetrics(namespace string) *metrics {
+	m := &metrics{
+		namespace: namespace,
+		registry:  prometheus.NewRegistry(),
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_total",
+			Help:      "Number of HTTP requests handled.",
+		}, []string{"method", "route", "status"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of HTTP requests in seconds.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		// the status is unknown while a request is in flight
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "http",
+			Name:      "requests_in_flight",
+			Help:      "Number of HTTP requests being handled.",
+		}, []string{"method", "route"}),
+	}
+
+	m.registry.MustRegister(
+		m.requests, m.duration, m.inFlight,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+
+	return m
+}
+
+// hedgeCancellations registers the counter of requests cancelled because a
+// hedged duplicate completed first
+func (m *metrics) hedgeCancellations() prometheus.Counter {
+	counter := prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: m.namespace,
+		Subsystem: "http",
+		Name:      "hedge_cancellations_total",
+		Help:      "Number of hedged requests cancelled after a duplicate completed.",
+	})
+	m.registry.MustRegister(counter)
+	return counter
+}
+
+// middleware records the metrics labeled by route template rather than raw
+// path to keep cardinality bounded; unmatched requests get an empty route
+func (m *metrics) middleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			start := time.Now()
+			method := c.Request().Method
+
+			route := c.Path()
+			gauge := m.inFlight.WithLabelValues(method, route)
+			gauge.Inc()
+			defer gauge.Dec()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			if err == echo.ErrNotFound {
+				route = ""
+			}
+			m.requests.WithLabelValues(method, route, strconv.Itoa(c.Response().Status)).Inc()
+			observeLatency(m.duration, c, err, time.Since(start))
+
+			return err
+		}
+	}
+}
+
+// RegisterMetricsEndpoint serves the metrics collected by WithMetrics in the
+// Prometheus exposition format at path
+func (s *Server) RegisterMetricsEndpoint(path string) error {
+	if s.metrics == nil {
+		return fmt.Errorf("metrics are not enabled, use WithMetrics")
+	}
+
+	handler := promhttp.HandlerFor(s.metrics.registry, promhttp.HandlerOpts{
+		EnableOpenMetrics: true,
+	})
+
+	rr := NewRouters()
+	if err := rr.AddRoute(RegisterRouter{
+		Path:    path,
+		Methods: Methods{http.MethodGet: echo.WrapHandler(handler)},
+		Summary: "Prometheus metrics",
+		builtin: true,
+	}); err != nil {
+		return err
+	}
+
+	return s.RegisterRouters(ROOT, rr)
+}
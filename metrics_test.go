@@ -0,0 +1,46 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMetrics(t *testing.T) {
+	server, err := NewServer(WithMetrics("shop"))
+	assert.NoError(t, err)
+	assert.Contains(t, server.Middlewares(), "metrics")
+
+	rr := NewRouters()
+	rr.AddRouter("/users/:id", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.Param("id"))
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+	assert.NoError(t, server.RegisterMetricsEndpoint("/metrics"))
+
+	e := server.GetEcho()
+	for _, path := range []string{"/users/1", "/users/2", "/missing"} {
+		e.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, path, nil))
+	}
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	body := rec.Body.String()
+	assert.Contains(t, body, `shop_http_requests_total{method="GET",route="/users/:id",status="200"} 2`)
+	assert.Contains(t, body, `shop_http_requests_total{method="GET",route="",status="404"} 1`)
+	assert.Contains(t, body, `shop_http_request_duration_seconds_count{method="GET",route="/users/:id",status="200"} 2`)
+	assert.Contains(t, body, `shop_http_requests_in_flight{method="GET",route="/metrics"} 1`)
+	assert.NotContains(t, body, `route="/users/1"`)
+	assert.Contains(t, body, "go_goroutines")
+}
+
+func TestRegisterMetricsEndpointWithoutMetrics(t *testing.T) {
+	server, _ := NewServer()
+	assert.Error(t, server.RegisterMetricsEndpoint("/metrics"))
+}
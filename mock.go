@@ -0,0 +1,284 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+	"gopkg.in/yaml.v3"
+)
+
+// openAPISpec is the subset of an OpenAPI 3 document needed to mock
+// responses
+type openAPISpec struct {
+	Paths      map[string]openAPIPathItem `yaml:"paths"`
+	Components struct {
+		Schemas map[string]*openAPISchema `yaml:"schemas"`
+	} `yaml:"components"`
+}
+
+type openAPIPathItem struct {
+	Get     *openAPIOperation `yaml:"get"`
+	Put     *openAPIOperation `yaml:"put"`
+	Post    *openAPIOperation `yaml:"post"`
+	Delete  *openAPIOperation `yaml:"delete"`
+	Options *openAPIOperation `yaml:"options"`
+	Head    *openAPIOperation `yaml:"head"`
+	Patch   *openAPIOperation `yaml:"patch"`
+	Trace   *openAPIOperation `yaml:"trace"`
+}
+
+type openAPIOperation struct {
+	Responses map[string]openAPIResponse `yaml:"responses"`
+}
+
+type openAPIResponse struct {
+	Content map[string]openAPIMedia `yaml:"content"`
+}
+
+type openAPIMedia struct {
+	Example any            `yaml:"example"`
+	Schema  *openAPISchema `yaml:"schema"`
+}
+
+type openAPISchema struct {
+	Ref        string                    `yaml:"$ref"`
+	Type       string                    `yaml:"type"`
+	Format     string                    `yaml:"format"`
+	Example    any                       `yaml:"example"`
+	Default    any                       `yaml:"default"`
+	Enum       []any                     `yaml:"enum"`
+	Properties map[string]*openAPISchema `yaml:"properties"`
+	Items      *openAPISchema            `yaml:"items"`
+	AllOf      []*openAPISchema          `yaml:"allOf"`
+}
+
+// maxSchemaDepth bounds example generation for recursive schemas
+const maxSchemaDepth = 10
+
+// mockResponse is the canned response of an operation
+type mockResponse struct {
+	status      int
+	contentType string
+	body        any
+}
+
+// openAPIMock serves example responses for the operations of a spec
+type openAPIMock struct {
+	spec *openAPISpec
+}
+
+func parseOpenAPIMock(spec []byte) (*openAPIMock, error) {
+	var doc openAPISpec
+	if err := yaml.Unmarshal(spec, &doc); err != nil {
+		return nil, fmt.Errorf("invalid openapi spec: %w", err)
+	}
+	if len(doc.Paths) == 0 {
+		return nil, fmt.Errorf("invalid openapi spec: no paths")
+	}
+	return &openAPIMock{spec: &doc}, nil
+}
+
+// middleware answers requests no route handles with the example response of
+// the matching spec operation. Registered handlers always take precedence.
+func (m *openAPIMock) middleware() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			err := next(c)
+			if err != echo.ErrNotFound && err != echo.ErrMethodNotAllowed {
+				return err
+			}
+
+			res, ok := m.lookup(c.Request().Method, c.Request().URL.Path)
+			if !ok {
+				return err
+			}
+
+			if res.body == nil {
+				return c.NoContent(res.status)
+			}
+
+			body, merr := json.Marshal(res.body)
+			if merr != nil {
+				return merr
+			}
+			return c.Blob(res.status, res.contentType, body)
+		}
+	}
+}
+
+// lookup finds the operation for the request, preferring the spec path with
+// the fewest parameters
+func (m *openAPIMock) lookup(method, path string) (mockResponse, bool) {
+	best, bestParams := "", -1
+	for template := range m.spec.Paths {
+		params, ok := matchTemplate(template, path)
+		if ok && (bestParams < 0 || params < bestParams) {
+			best, bestParams = template, params
+		}
+	}
+	if bestParams < 0 {
+		return mockResponse{}, false
+	}
+
+	op := m.spec.Paths[best].operation(method)
+	if op == nil {
+		return mockResponse{}, false
+	}
+
+	return m.example(op)
+}
+
+func (p openAPIPathItem) operation(method string) *openAPIOperation {
+	return map[string]*openAPIOperation{
+		http.MethodGet:     p.Get,
+		http.MethodPut:     p.Put,
+		http.MethodPost:    p.Post,
+		http.MethodDelete:  p.Delete,
+		http.MethodOptions: p.Options,
+		http.MethodHead:    p.Head,
+		http.MethodPatch:   p.Patch,
+		http.MethodTrace:   p.Trace,
+	}[method]
+}
+
+// matchTemplate matches a path against an OpenAPI path template such as
+// /users/{id}, returning the number of template params
+func matchTemplate(template, path string) (int, bool) {
+	tparts := strings.Split(strings.Trim(template, "/"), "/")
+	pparts := strings.Split(strings.Trim(path, "/"), "/")
+	if len(tparts) != len(pparts) {
+		return 0, false
+	}
+
+	params := 0
+	for i, part := range tparts {
+		if strings.HasPrefix(part, "{") && strings.HasSuffix(part, "}") {
+			if len(pparts[i]) == 0 {
+				return 0, false
+			}
+			params++
+			continue
+		}
+		if part != pparts[i] {
+			return 0, false
+		}
+	}
+
+	return params, true
+}
+
+// example builds the response of the lowest 2xx status, falling back to
+// the default response
+func (m *openAPIMock) example(op *openAPIOperation) (mockResponse, bool) {
+	codes := make([]string, 0, len(op.Responses))
+	for code := range op.Responses {
+		if strings.HasPrefix(code, "2") {
+			codes = append(codes, code)
+		}
+	}
+	sort.Strings(codes)
+
+	status, code := http.StatusOK, "default"
+	if len(codes) > 0 {
+		code = codes[0]
+		if n, err := strconv.Atoi(code); err == nil {
+			status = n
+		}
+	}
+
+	res, ok := op.Responses[code]
+	if !ok {
+		return mockResponse{}, false
+	}
+
+	contentType := echo.MIMEApplicationJSON
+	media, ok := res.Content[contentType]
+	if !ok {
+		for ct, mt := range res.Content {
+			contentType, media, ok = ct, mt, true
+			break
+		}
+	}
+	if !ok {
+		return mockResponse{status: status}, true
+	}
+
+	body := media.Example
+	if body == nil {
+		body = m.schemaExample(media.Schema, 0)
+	}
+
+	return mockResponse{status: status, contentType: contentType, body: body}, true
+}
+
+// schemaExample generates an example value for a schema
+func (m *openAPIMock) schemaExample(schema *openAPISchema, depth int) any {
+	if schema == nil || depth > maxSchemaDepth {
+		return nil
+	}
+
+	if len(schema.Ref) > 0 {
+		name := strings.TrimPrefix(schema.Ref, "#/components/schemas/")
+		return m.schemaExample(m.spec.Components.Schemas[name], depth+1)
+	}
+
+	switch {
+	case schema.Example != nil:
+		return schema.Example
+	case schema.Default != nil:
+		return schema.Default
+	case len(schema.Enum) > 0:
+		return schema.Enum[0]
+	}
+
+	if len(schema.AllOf) > 0 {
+		merged := map[string]any{}
+		for _, part := range schema.AllOf {
+			if obj, ok := m.schemaExample(part, depth+1).(map[string]any); ok {
+				for k, v := range obj {
+					merged[k] = v
+				}
+			}
+		}
+		return merged
+	}
+
+	switch schema.Type {
+	case "array":
+		return []any{m.schemaExample(schema.Items, depth+1)}
+	case "string":
+		return stringExample(schema.Format)
+	case "integer", "number":
+		return 0
+	case "boolean":
+		return false
+	}
+
+	obj := make(map[string]any, len(schema.Properties))
+	for name, prop := range schema.Properties {
+		obj[name] = m.schemaExample(prop, depth+1)
+	}
+	return obj
+}
+
+func stringExample(format string) string {
+	switch format {
+	case "date-time":
+		return "1970-01-01T00:00:00Z"
+	case "date":
+		return "1970-01-01"
+	case "uuid":
+		return "00000000-0000-0000-0000-000000000000"
+	case "email":
+		return "user@example.com"
+	case "uri":
+		return "https://example.com"
+	default:
+		return "string"
+	}
+}
@@ -0,0 +1,152 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const mockSpec = `
+openapi: 3.0.3
+info:
+  title: Users
+  version: 1.0.0
+paths:
+  /v1/users:
+    post:
+      responses:
+        "201":
+          content:
+            application/json:
+              example: {"id": 42}
+  /v1/users/{id}:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              schema:
+                $ref: "#/components/schemas/User"
+        "404":
+          description: not found
+    delete:
+      responses:
+        "204":
+          description: deleted
+  /v1/users/me:
+    get:
+      responses:
+        default:
+          content:
+            application/json:
+              schema:
+                type: object
+                properties:
+                  name:
+                    type: string
+                    example: me
+  /v1/health:
+    get:
+      responses:
+        "200":
+          content:
+            application/json:
+              example: {"status": "mocked"}
+components:
+  schemas:
+    User:
+      type: object
+      properties:
+        id:
+          type: integer
+        name:
+          type: string
+          example: Ada
+        email:
+          type: string
+          format: email
+        role:
+          type: string
+          enum: [admin, member]
+        tags:
+          type: array
+          items:
+            type: string
+        manager:
+          $ref: "#/components/schemas/User"
+`
+
+func TestWithMockFromOpenAPI(t *testing.T) {
+	server, err := NewServer(WithMockFromOpenAPI([]byte(mockSpec)))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/health", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"status": "ok"})
+		},
+	})
+	_ = server.RegisterRouters(V1, rr)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Real handler wins", http.MethodGet, "/v1/health", http.StatusOK, `{"status":"ok"}`},
+		{"Example from response", http.MethodPost, "/v1/users", http.StatusCreated, `{"id":42}`},
+		{"Literal path preferred", http.MethodGet, "/v1/users/me", http.StatusOK, `{"name":"me"}`},
+		{"No content", http.MethodDelete, "/v1/users/7", http.StatusNoContent, ""},
+		{"Unknown method", http.MethodPut, "/v1/users/7", http.StatusNotFound, ""},
+		{"Unknown path", http.MethodGet, "/v1/orders", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if len(tt.expectedBody) > 0 {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithMockFromOpenAPISchemaExample(t *testing.T) {
+	server, _ := NewServer(WithMockFromOpenAPI([]byte(mockSpec)))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/7", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Header().Get("Content-Type"), "application/json")
+
+	var user map[string]any
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &user))
+	assert.Equal(t, float64(0), user["id"])
+	assert.Equal(t, "Ada", user["name"])
+	assert.Equal(t, "user@example.com", user["email"])
+	assert.Equal(t, "admin", user["role"])
+	assert.Equal(t, []any{"string"}, user["tags"])
+	assert.IsType(t, map[string]any{}, user["manager"])
+}
+
+func TestWithMockFromOpenAPIInvalid(t *testing.T) {
+	_, err := NewServer(WithMockFromOpenAPI(nil))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithMockFromOpenAPI([]byte("paths: [")))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithMockFromOpenAPI([]byte(`{"openapi": "3.0.3"}`)))
+	assert.Error(t, err)
+}
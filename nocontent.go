@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NoContentWithHeaders answers 204 No Content with the given headers, such
+// as Location or ETag. Any Content-Type set by earlier middlewares is
+// dropped and Content-Length is pinned to 0 so the response carries no body.
+func NoContentWithHeaders(c Context, headers map[string]string) error {
+	h := c.Response().Header()
+	for key, value := range headers {
+		h.Set(key, value)
+	}
+	h.Del(echo.HeaderContentType)
+	h.Set(echo.HeaderContentLength, "0")
+
+	return c.NoContent(http.StatusNoContent)
+}
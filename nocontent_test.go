@@ -0,0 +1,49 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoContentWithHeaders(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/users/:id", Methods{
+		http.MethodPut: func(c Context) error {
+			c.Response().Header().Set("Content-Type", "application/json")
+			return NoContentWithHeaders(c, map[string]string{
+				"Location": "/users/" + c.Param("id"),
+				"ETag":     `"v2"`,
+			})
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	ts := httptest.NewServer(server.GetEcho())
+	defer ts.Close()
+
+	req, _ := http.NewRequest(http.MethodPut, ts.URL+"/users/42", nil)
+	res, err := http.DefaultClient.Do(req)
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer res.Body.Close()
+	body, _ := io.ReadAll(res.Body)
+
+	assert.Equal(t, http.StatusNoContent, res.StatusCode)
+	assert.Equal(t, "/users/42", res.Header.Get("Location"))
+	assert.Equal(t, `"v2"`, res.Header.Get("ETag"))
+	assert.Empty(t, res.Header.Get("Content-Type"))
+	assert.Empty(t, body)
+
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/users/42", nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "0", rec.Header().Get("Content-Length"))
+	assert.Empty(t, rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String())
+}
@@ -1,7 +1,23 @@
 package server
 
 import (
+	"compress/gzip"
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
 	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/gommon/bytes"
+	"github.com/prometheus/client_golang/prometheus"
+	"golang.org/x/net/http/httpguts"
+	"google.golang.org/grpc"
 )
 
 type Options func(s *ServerParams) error
@@ -10,6 +26,117 @@ type ServerParams struct {
 	Port string
 	Host string
 	Slog *slog.SugaredLogger
+
+	RecentRequests int
+	GRPCWeb        *grpc.Server
+	BufferedWriter int
+	Auth           []ScopedMiddleware
+	BodyDrain      int64
+	CanonicalHost  string
+	CanonicalProxy []*net.IPNet
+
+	DuplicateWindow time.Duration
+	DuplicateKey    DuplicateKeyFunc
+
+	IncompleteResponses bool
+	MaxRoutes           int
+	AccessLog           bool
+	ErrorLog            bool
+	MaxConnections      int
+	Compression         bool
+	CompressionLevel    int
+	CompressionEncoding []string
+	RetryCount          bool
+	MaxRetryCount       int
+
+	TLSMinVersion   uint16
+	TLSCipherSuites []uint16
+
+	PayloadTransforms []PayloadTransform
+	ShutdownGrace     time.Duration
+	ShutdownTimeout   time.Duration
+	ShutdownSignals   []os.Signal
+	RequestRecorder   string
+
+	ReplayStore  ReplayStore
+	ReplayHeader string
+
+	ReadTimeout       time.Duration
+	ReadHeaderTimeout time.Duration
+	WriteTimeout      time.Duration
+	IdleTimeout       time.Duration
+
+	MockSpec []byte
+
+	TLSCertFile    string
+	TLSKeyFile     string
+	AutoTLSDomains []string
+
+	LatencyHistogram *prometheus.HistogramVec
+
+	SPADir         string
+	SPAAPIPrefixes []string
+
+	Baggage bool
+
+	ResponseTransform func(status int, body []byte) []byte
+
+	Recover bool
+
+	CORSOrigins    []string
+	CORSOriginFunc func(origin string) (bool, error)
+
+	GoroutineLeakDetection bool
+
+	Metrics          bool
+	MetricsNamespace string
+
+	AccessLogFormat string
+	AccessLogOutput io.Writer
+
+	BodyLimit string
+
+	GroupErrorHandlers map[Kind]GroupErrorHandler
+
+	BandwidthLimit int
+	BandwidthKey   BandwidthKeyFunc
+
+	GroupMiddlewares map[Kind][]MiddlewareFunc
+
+	WebSocketReadLimit int64
+	WebSocketPing      time.Duration
+
+	RequestID bool
+
+	Validator Validator
+
+	AdminPrefix string
+	AdminAuth   MiddlewareFunc
+
+	JSONSerializer JSONSerializer
+
+	StrictQueryParams bool
+
+	CompressionMinLength int
+	CompressionSkipper   func(c Context) bool
+
+	TrustedProxies []*net.IPNet
+
+	Hedging     bool
+	HedgeHeader string
+
+	Listener net.Listener
+
+	MinProtocol   string
+	MinTLSVersion uint16
+
+	JSONUseNumber bool
+
+	PreShutdownDelay time.Duration
+
+	RequireCorrelationID []ScopedMiddleware
+
+	ReplayGroups []Kind
 }
 
 func newServerParams(opts ...Options) (*ServerParams, error) {
@@ -44,28 +171,977 @@ func WithSlog(slog *slog.SugaredLogger) Options {
 	}
 }
 
-// getters and setters ------
+func WithRecentRequests(size int) Options {
+	return func(s *ServerParams) error {
+		if size <= 0 {
+			return fmt.Errorf("recent requests size must be positive, got %d", size)
+		}
+		s.RecentRequests = size
+		return nil
+	}
+}
 
-func (s *ServerParams) GetPort() string {
-	return s.Port
+func WithGRPCWeb(grpcServer *grpc.Server) Options {
+	return func(s *ServerParams) error {
+		if grpcServer == nil {
+			return fmt.Errorf("grpc server is nil")
+		}
+		s.GRPCWeb = grpcServer
+		return nil
+	}
 }
 
-func (s *ServerParams) GetHost() string {
-	return s.Host
+func WithBufferedWriter(size int) Options {
+	return func(s *ServerParams) error {
+		if size <= 0 {
+			return fmt.Errorf("buffered writer size must be positive, got %d", size)
+		}
+		s.BufferedWriter = size
+		return nil
+	}
 }
 
-func (s *ServerParams) SetPort(port string) {
-	s.Port = port
+func WithBodyDrain(limit int64) Options {
+	return func(s *ServerParams) error {
+		if limit <= 0 {
+			return fmt.Errorf("body drain limit must be positive, got %d", limit)
+		}
+		s.BodyDrain = limit
+		return nil
+	}
 }
 
-func (s *ServerParams) SetHost(host string) {
-	s.Host = host
+func WithCanonicalHost(host string, trustedProxies ...string) Options {
+	return func(s *ServerParams) error {
+		if _, err := parseCanonicalHost(host); err != nil {
+			return err
+		}
+
+		for _, cidr := range trustedProxies {
+			_, ipnet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+			}
+			s.CanonicalProxy = append(s.CanonicalProxy, ipnet)
+		}
+
+		s.CanonicalHost = host
+		return nil
+	}
 }
 
-func (s *ServerParams) GetSlog() *slog.SugaredLogger {
-	return s.Slog
+func WithDuplicateDetection(window time.Duration, keyFunc DuplicateKeyFunc) Options {
+	return func(s *ServerParams) error {
+		if window <= 0 {
+			return fmt.Errorf("duplicate detection window must be positive, got %s", window)
+		}
+		s.DuplicateWindow = window
+		s.DuplicateKey = keyFunc
+		return nil
+	}
 }
 
-func (s *ServerParams) SetSlog(slog *slog.SugaredLogger) {
-	s.Slog = slog
+func WithIncompleteResponses() Options {
+	return func(s *ServerParams) error {
+		s.IncompleteResponses = true
+		return nil
+	}
+}
+
+func WithMaxRoutes(n int) Options {
+	return func(s *ServerParams) error {
+		if n <= 0 {
+			return fmt.Errorf("max routes must be positive, got %d", n)
+		}
+		s.MaxRoutes = n
+		return nil
+	}
+}
+
+func WithAccessLog() Options {
+	return func(s *ServerParams) error {
+		s.AccessLog = true
+		return nil
+	}
+}
+
+func WithErrorLog() Options {
+	return func(s *ServerParams) error {
+		s.ErrorLog = true
+		return nil
+	}
+}
+
+func WithMaxConnections(n int) Options {
+	return func(s *ServerParams) error {
+		if n <= 0 {
+			return fmt.Errorf("max connections must be positive, got %d", n)
+		}
+		s.MaxConnections = n
+		return nil
+	}
+}
+
+func WithCompression(level int, encodings ...string) Options {
+	return func(s *ServerParams) error {
+		if level < gzip.HuffmanOnly || level > gzip.BestCompression {
+			return fmt.Errorf("invalid compression level: %d", level)
+		}
+
+		seen := map[string]bool{}
+		for _, encoding := range encodings {
+			if encoding != EncodingGzip && encoding != EncodingBrotli {
+				return fmt.Errorf("unsupported compression encoding: %q", encoding)
+			}
+			if seen[encoding] {
+				return fmt.Errorf("duplicate compression encoding: %q", encoding)
+			}
+			seen[encoding] = true
+		}
+
+		s.Compression = true
+		s.CompressionLevel = level
+		s.CompressionEncoding = encodings
+		return nil
+	}
+}
+
+func WithRetryCount(limit int) Options {
+	return func(s *ServerParams) error {
+		if limit < 0 {
+			return fmt.Errorf("retry count limit must not be negative, got %d", limit)
+		}
+		s.RetryCount = true
+		s.MaxRetryCount = limit
+		return nil
+	}
+}
+
+func WithTLSConfig(min uint16, ciphers []uint16) Options {
+	return func(s *ServerParams) error {
+		if min < tls.VersionTLS10 || min > tls.VersionTLS13 {
+			return fmt.Errorf("invalid tls version: %#04x", min)
+		}
+
+		secure := make(map[uint16]bool)
+		for _, suite := range tls.CipherSuites() {
+			secure[suite.ID] = true
+		}
+		for _, id := range ciphers {
+			if !secure[id] {
+				return fmt.Errorf("unsupported or insecure cipher suite: %s", tls.CipherSuiteName(id))
+			}
+		}
+
+		s.TLSMinVersion = min
+		s.TLSCipherSuites = ciphers
+		return nil
+	}
+}
+
+func WithPayloadTransform(group Kind, up, down func([]byte) ([]byte, error)) Options {
+	return func(s *ServerParams) error {
+		if group < ROOT || group > DOCS {
+			return fmt.Errorf("invalid group type")
+		}
+		if up == nil && down == nil {
+			return fmt.Errorf("payload transform needs an up or down function")
+		}
+		s.PayloadTransforms = append(s.PayloadTransforms, PayloadTransform{
+			Group: group,
+			Up:    up,
+			Down:  down,
+		})
+		return nil
+	}
+}
+
+func WithShutdownGrace(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("shutdown grace must be positive, got %s", d)
+		}
+		s.ShutdownGrace = d
+		return nil
+	}
+}
+
+func WithRequestRecorder(dir string) Options {
+	return func(s *ServerParams) error {
+		info, err := os.Stat(dir)
+		if err != nil {
+			return fmt.Errorf("request recorder: %w", err)
+		}
+		if !info.IsDir() {
+			return fmt.Errorf("request recorder: %s is not a directory", dir)
+		}
+		s.RequestRecorder = dir
+		return nil
+	}
+}
+
+func WithReplayProtection(store ReplayStore, header string, opts ...AuthOption) Options {
+	return func(s *ServerParams) error {
+		if store == nil {
+			return fmt.Errorf("replay protection needs a store")
+		}
+		if len(header) == 0 {
+			header = echo.HeaderXRequestID
+		}
+
+		var scope ScopedMiddleware
+		for _, opt := range opts {
+			opt(&scope)
+		}
+		for _, group := range scope.Groups {
+			if group < ROOT || group > DOCS {
+				return fmt.Errorf("invalid group type")
+			}
+		}
+
+		s.ReplayStore = store
+		s.ReplayHeader = header
+		s.ReplayGroups = scope.Groups
+		return nil
+	}
+}
+
+func WithReadTimeout(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("read timeout must be positive, got %s", d)
+		}
+		s.ReadTimeout = d
+		return nil
+	}
+}
+
+func WithReadHeaderTimeout(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("read header timeout must be positive, got %s", d)
+		}
+		s.ReadHeaderTimeout = d
+		return nil
+	}
+}
+
+func WithWriteTimeout(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("write timeout must be positive, got %s", d)
+		}
+		s.WriteTimeout = d
+		return nil
+	}
+}
+
+func WithIdleTimeout(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("idle timeout must be positive, got %s", d)
+		}
+		s.IdleTimeout = d
+		return nil
+	}
+}
+
+func WithMockFromOpenAPI(spec []byte) Options {
+	return func(s *ServerParams) error {
+		if len(spec) == 0 {
+			return fmt.Errorf("openapi spec is empty")
+		}
+		s.MockSpec = spec
+		return nil
+	}
+}
+
+func WithTLS(certFile, keyFile string) Options {
+	return func(s *ServerParams) error {
+		if len(certFile) == 0 || len(keyFile) == 0 {
+			return fmt.Errorf("tls needs both a certificate and a key file")
+		}
+		if len(s.AutoTLSDomains) > 0 {
+			return fmt.Errorf("tls and auto tls are mutually exclusive")
+		}
+		for _, file := range []string{certFile, keyFile} {
+			if _, err := os.Stat(file); err != nil {
+				return fmt.Errorf("tls: %w", err)
+			}
+		}
+		s.TLSCertFile = certFile
+		s.TLSKeyFile = keyFile
+		return nil
+	}
+}
+
+func WithAutoTLS(domains ...string) Options {
+	return func(s *ServerParams) error {
+		if len(domains) == 0 {
+			return fmt.Errorf("auto tls needs at least one domain")
+		}
+		if len(s.TLSCertFile) > 0 {
+			return fmt.Errorf("tls and auto tls are mutually exclusive")
+		}
+		s.AutoTLSDomains = domains
+		return nil
+	}
+}
+
+func WithLatencyHistogram(h *prometheus.HistogramVec) Options {
+	return func(s *ServerParams) error {
+		if h == nil {
+			return fmt.Errorf("latency histogram is nil")
+		}
+		labels := prometheus.Labels{"method": "", "route": "", "status": ""}
+		if _, err := h.GetMetricWith(labels); err != nil {
+			return fmt.Errorf("latency histogram needs method, route and status labels: %w", err)
+		}
+		h.Delete(labels)
+		s.LatencyHistogram = h
+		return nil
+	}
+}
+
+func WithShutdownTimeout(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("shutdown timeout must be positive, got %s", d)
+		}
+		s.ShutdownTimeout = d
+		return nil
+	}
+}
+
+func WithSPA(dir string, apiPrefixes []string) Options {
+	return func(s *ServerParams) error {
+		if _, err := os.Stat(filepath.Join(dir, "index.html")); err != nil {
+			return fmt.Errorf("spa: %w", err)
+		}
+		s.SPADir = dir
+		s.SPAAPIPrefixes = apiPrefixes
+		return nil
+	}
+}
+
+func WithBaggage() Options {
+	return func(s *ServerParams) error {
+		s.Baggage = true
+		return nil
+	}
+}
+
+func WithShutdownSignals(sigs ...os.Signal) Options {
+	return func(s *ServerParams) error {
+		if len(sigs) == 0 {
+			return fmt.Errorf("shutdown signals must not be empty")
+		}
+		s.ShutdownSignals = sigs
+		return nil
+	}
+}
+
+func WithResponseTransform(fn func(status int, body []byte) []byte) Options {
+	return func(s *ServerParams) error {
+		if fn == nil {
+			return fmt.Errorf("response transform is nil")
+		}
+		s.ResponseTransform = fn
+		return nil
+	}
+}
+
+func WithRecover(enabled bool) Options {
+	return func(s *ServerParams) error {
+		s.Recover = enabled
+		return nil
+	}
+}
+
+func WithCORS(origins []string, allowOrigin func(origin string) (bool, error)) Options {
+	return func(s *ServerParams) error {
+		if len(origins) == 0 && allowOrigin == nil {
+			return fmt.Errorf("cors needs allowed origins or an origin func")
+		}
+		s.CORSOrigins = origins
+		s.CORSOriginFunc = allowOrigin
+		return nil
+	}
+}
+
+func WithGoroutineLeakDetection() Options {
+	return func(s *ServerParams) error {
+		s.GoroutineLeakDetection = true
+		return nil
+	}
+}
+
+func WithMetrics(namespace string) Options {
+	return func(s *ServerParams) error {
+		if len(namespace) > 0 && !metricNamespace.MatchString(namespace) {
+			return fmt.Errorf("invalid metrics namespace: %q", namespace)
+		}
+		s.Metrics = true
+		s.MetricsNamespace = namespace
+		return nil
+	}
+}
+
+func WithAccessLogFormat(format string, w io.Writer) Options {
+	return func(s *ServerParams) error {
+		switch format {
+		case AccessLogJSON, AccessLogLogfmt, AccessLogCombined:
+		default:
+			return fmt.Errorf("unsupported access log format: %q", format)
+		}
+		if w == nil {
+			w = os.Stdout
+		}
+		s.AccessLog = true
+		s.AccessLogFormat = format
+		s.AccessLogOutput = w
+		return nil
+	}
+}
+
+func WithBodyLimit(limit string) Options {
+	return func(s *ServerParams) error {
+		if _, err := bytes.Parse(limit); err != nil {
+			return fmt.Errorf("invalid body limit %q: %w", limit, err)
+		}
+		s.BodyLimit = limit
+		return nil
+	}
+}
+
+func WithGroupErrorHandler(group Kind, handler GroupErrorHandler) Options {
+	return func(s *ServerParams) error {
+		if group < ROOT || group > DOCS {
+			return fmt.Errorf("invalid group type")
+		}
+		if handler == nil {
+			return fmt.Errorf("group error handler is nil")
+		}
+		if s.GroupErrorHandlers == nil {
+			s.GroupErrorHandlers = map[Kind]GroupErrorHandler{}
+		}
+		s.GroupErrorHandlers[group] = handler
+		return nil
+	}
+}
+
+func WithBandwidthLimit(bytesPerSec int, keyFunc BandwidthKeyFunc) Options {
+	return func(s *ServerParams) error {
+		if bytesPerSec <= 0 {
+			return fmt.Errorf("bandwidth limit must be positive, got %d", bytesPerSec)
+		}
+		s.BandwidthLimit = bytesPerSec
+		s.BandwidthKey = keyFunc
+		return nil
+	}
+}
+
+func WithLoggingProfile(profile string) Options {
+	return func(s *ServerParams) error {
+		logger, err := NewProfileLogger(profile, os.Stdout)
+		if err != nil {
+			return err
+		}
+		s.Slog = logger
+		return nil
+	}
+}
+
+func WithGroupMiddleware(group Kind, middlewares ...MiddlewareFunc) Options {
+	return func(s *ServerParams) error {
+		if group < ROOT || group > DOCS {
+			return fmt.Errorf("invalid group type")
+		}
+		if s.GroupMiddlewares == nil {
+			s.GroupMiddlewares = map[Kind][]MiddlewareFunc{}
+		}
+		s.GroupMiddlewares[group] = append(s.GroupMiddlewares[group], middlewares...)
+		return nil
+	}
+}
+
+func WithWebSocketConfig(readLimit int64, ping time.Duration) Options {
+	return func(s *ServerParams) error {
+		if readLimit <= 0 {
+			return fmt.Errorf("websocket read limit must be positive, got %d", readLimit)
+		}
+		if ping <= 0 {
+			return fmt.Errorf("websocket ping interval must be positive, got %s", ping)
+		}
+		s.WebSocketReadLimit = readLimit
+		s.WebSocketPing = ping
+		return nil
+	}
+}
+
+func WithRequestID() Options {
+	return func(s *ServerParams) error {
+		s.RequestID = true
+		return nil
+	}
+}
+
+func WithValidator(v Validator) Options {
+	return func(s *ServerParams) error {
+		if v == nil {
+			return fmt.Errorf("validator is nil")
+		}
+		s.Validator = v
+		return nil
+	}
+}
+
+func WithAdmin(prefix string, auth MiddlewareFunc) Options {
+	return func(s *ServerParams) error {
+		if !strings.HasPrefix(prefix, "/") || prefix == "/" {
+			return fmt.Errorf("admin prefix must be a path below /, got %q", prefix)
+		}
+		if auth == nil {
+			return fmt.Errorf("admin endpoints need an auth middleware")
+		}
+		s.AdminPrefix = prefix
+		s.AdminAuth = auth
+		return nil
+	}
+}
+
+func WithJSONSerializer(serializer JSONSerializer) Options {
+	return func(s *ServerParams) error {
+		if serializer == nil {
+			return fmt.Errorf("json serializer is nil")
+		}
+		if s.JSONUseNumber {
+			return fmt.Errorf("json serializer and json use number are mutually exclusive")
+		}
+		s.JSONSerializer = serializer
+		return nil
+	}
+}
+
+func WithStrictQueryParams() Options {
+	return func(s *ServerParams) error {
+		s.StrictQueryParams = true
+		return nil
+	}
+}
+
+func WithCompressionMinLength(n int) Options {
+	return func(s *ServerParams) error {
+		if n < 0 {
+			return fmt.Errorf("compression min length must not be negative, got %d", n)
+		}
+		s.CompressionMinLength = n
+		return nil
+	}
+}
+
+func WithCompressionSkipper(skip func(c Context) bool) Options {
+	return func(s *ServerParams) error {
+		if skip == nil {
+			return fmt.Errorf("compression skipper is nil")
+		}
+		s.CompressionSkipper = skip
+		return nil
+	}
+}
+
+func WithTrustedProxies(cidrs ...string) Options {
+	return func(s *ServerParams) error {
+		if len(cidrs) == 0 {
+			return fmt.Errorf("no trusted proxies given")
+		}
+		for _, cidr := range cidrs {
+			_, ipnet, err := net.ParseCIDR(cidr)
+			if err != nil {
+				return fmt.Errorf("invalid trusted proxy %q: %w", cidr, err)
+			}
+			s.TrustedProxies = append(s.TrustedProxies, ipnet)
+		}
+		return nil
+	}
+}
+
+func WithHedging(header string) Options {
+	return func(s *ServerParams) error {
+		if len(header) > 0 && !httpguts.ValidHeaderFieldName(header) {
+			return fmt.Errorf("invalid hedge header name %q", header)
+		}
+		s.Hedging = true
+		s.HedgeHeader = header
+		return nil
+	}
+}
+
+func WithListener(l net.Listener) Options {
+	return func(s *ServerParams) error {
+		if l == nil {
+			return fmt.Errorf("listener is nil")
+		}
+		s.Listener = l
+		return nil
+	}
+}
+
+func WithUpgradeRequired(minProto string, minTLS uint16) Options {
+	return func(s *ServerParams) error {
+		if len(minProto) == 0 && minTLS == 0 {
+			return fmt.Errorf("upgrade required needs a minimum protocol or tls version")
+		}
+		if _, _, ok := http.ParseHTTPVersion(minProto); len(minProto) > 0 && !ok {
+			return fmt.Errorf("invalid http version: %q", minProto)
+		}
+		if minTLS != 0 && (minTLS < tls.VersionTLS10 || minTLS > tls.VersionTLS13) {
+			return fmt.Errorf("invalid tls version: %#04x", minTLS)
+		}
+		s.MinProtocol = minProto
+		s.MinTLSVersion = minTLS
+		return nil
+	}
+}
+
+func WithJSONUseNumber() Options {
+	return func(s *ServerParams) error {
+		if s.JSONSerializer != nil {
+			return fmt.Errorf("json serializer and json use number are mutually exclusive")
+		}
+		s.JSONUseNumber = true
+		return nil
+	}
+}
+
+func WithPreShutdownDelay(d time.Duration) Options {
+	return func(s *ServerParams) error {
+		if d <= 0 {
+			return fmt.Errorf("pre-shutdown delay must be positive, got %s", d)
+		}
+		s.PreShutdownDelay = d
+		return nil
+	}
+}
+
+// getters and setters ------
+
+func (s *ServerParams) GetPort() string {
+	return s.Port
+}
+
+func (s *ServerParams) GetHost() string {
+	return s.Host
+}
+
+func (s *ServerParams) SetPort(port string) {
+	s.Port = port
+}
+
+func (s *ServerParams) SetHost(host string) {
+	s.Host = host
+}
+
+func (s *ServerParams) GetSlog() *slog.SugaredLogger {
+	return s.Slog
+}
+
+func (s *ServerParams) SetSlog(slog *slog.SugaredLogger) {
+	s.Slog = slog
+}
+
+func (s *ServerParams) GetRecentRequests() int {
+	return s.RecentRequests
+}
+
+func (s *ServerParams) GetGRPCWeb() *grpc.Server {
+	return s.GRPCWeb
+}
+
+func (s *ServerParams) GetBufferedWriter() int {
+	return s.BufferedWriter
+}
+
+func (s *ServerParams) GetAuth() []ScopedMiddleware {
+	return s.Auth
+}
+
+func (s *ServerParams) GetBodyDrain() int64 {
+	return s.BodyDrain
+}
+
+func (s *ServerParams) GetCanonicalHost() string {
+	return s.CanonicalHost
+}
+
+func (s *ServerParams) GetCanonicalProxy() []*net.IPNet {
+	return s.CanonicalProxy
+}
+
+func (s *ServerParams) GetDuplicateWindow() time.Duration {
+	return s.DuplicateWindow
+}
+
+func (s *ServerParams) GetDuplicateKey() DuplicateKeyFunc {
+	return s.DuplicateKey
+}
+
+func (s *ServerParams) GetIncompleteResponses() bool {
+	return s.IncompleteResponses
+}
+
+func (s *ServerParams) GetMaxRoutes() int {
+	return s.MaxRoutes
+}
+
+func (s *ServerParams) GetAccessLog() bool {
+	return s.AccessLog
+}
+
+func (s *ServerParams) GetErrorLog() bool {
+	return s.ErrorLog
+}
+
+func (s *ServerParams) GetMaxConnections() int {
+	return s.MaxConnections
+}
+
+func (s *ServerParams) GetCompression() bool {
+	return s.Compression
+}
+
+func (s *ServerParams) GetCompressionLevel() int {
+	return s.CompressionLevel
+}
+
+func (s *ServerParams) GetCompressionEncoding() []string {
+	return s.CompressionEncoding
+}
+
+func (s *ServerParams) GetRetryCount() bool {
+	return s.RetryCount
+}
+
+func (s *ServerParams) GetMaxRetryCount() int {
+	return s.MaxRetryCount
+}
+
+func (s *ServerParams) GetTLSMinVersion() uint16 {
+	return s.TLSMinVersion
+}
+
+func (s *ServerParams) GetTLSCipherSuites() []uint16 {
+	return s.TLSCipherSuites
+}
+
+func (s *ServerParams) GetPayloadTransforms() []PayloadTransform {
+	return s.PayloadTransforms
+}
+
+func (s *ServerParams) GetShutdownGrace() time.Duration {
+	return s.ShutdownGrace
+}
+
+func (s *ServerParams) GetRequestRecorder() string {
+	return s.RequestRecorder
+}
+
+func (s *ServerParams) GetReplayStore() ReplayStore {
+	return s.ReplayStore
+}
+
+func (s *ServerParams) GetReplayHeader() string {
+	return s.ReplayHeader
+}
+
+func (s *ServerParams) GetReadTimeout() time.Duration {
+	return s.ReadTimeout
+}
+
+func (s *ServerParams) GetReadHeaderTimeout() time.Duration {
+	return s.ReadHeaderTimeout
+}
+
+func (s *ServerParams) GetWriteTimeout() time.Duration {
+	return s.WriteTimeout
+}
+
+func (s *ServerParams) GetIdleTimeout() time.Duration {
+	return s.IdleTimeout
+}
+
+func (s *ServerParams) GetMockSpec() []byte {
+	return s.MockSpec
+}
+
+func (s *ServerParams) GetTLSCertFile() string {
+	return s.TLSCertFile
+}
+
+func (s *ServerParams) GetTLSKeyFile() string {
+	return s.TLSKeyFile
+}
+
+func (s *ServerParams) GetAutoTLSDomains() []string {
+	return s.AutoTLSDomains
+}
+
+func (s *ServerParams) GetLatencyHistogram() *prometheus.HistogramVec {
+	return s.LatencyHistogram
+}
+
+func (s *ServerParams) GetShutdownTimeout() time.Duration {
+	return s.ShutdownTimeout
+}
+
+func (s *ServerParams) GetSPADir() string {
+	return s.SPADir
+}
+
+func (s *ServerParams) GetSPAAPIPrefixes() []string {
+	return s.SPAAPIPrefixes
+}
+
+func (s *ServerParams) GetBaggage() bool {
+	return s.Baggage
+}
+
+func (s *ServerParams) GetShutdownSignals() []os.Signal {
+	return s.ShutdownSignals
+}
+
+func (s *ServerParams) GetResponseTransform() func(status int, body []byte) []byte {
+	return s.ResponseTransform
+}
+
+func (s *ServerParams) GetRecover() bool {
+	return s.Recover
+}
+
+func (s *ServerParams) GetCORSOrigins() []string {
+	return s.CORSOrigins
+}
+
+func (s *ServerParams) GetCORSOriginFunc() func(origin string) (bool, error) {
+	return s.CORSOriginFunc
+}
+
+func (s *ServerParams) GetGoroutineLeakDetection() bool {
+	return s.GoroutineLeakDetection
+}
+
+func (s *ServerParams) GetMetrics() bool {
+	return s.Metrics
+}
+
+func (s *ServerParams) GetMetricsNamespace() string {
+	return s.MetricsNamespace
+}
+
+func (s *ServerParams) GetAccessLogFormat() string {
+	return s.AccessLogFormat
+}
+
+func (s *ServerParams) GetAccessLogOutput() io.Writer {
+	return s.AccessLogOutput
+}
+
+func (s *ServerParams) GetBodyLimit() string {
+	return s.BodyLimit
+}
+
+func (s *ServerParams) GetGroupErrorHandlers() map[Kind]GroupErrorHandler {
+	return s.GroupErrorHandlers
+}
+
+func (s *ServerParams) GetBandwidthLimit() int {
+	return s.BandwidthLimit
+}
+
+func (s *ServerParams) GetBandwidthKey() BandwidthKeyFunc {
+	return s.BandwidthKey
+}
+
+func (s *ServerParams) GetGroupMiddlewares() map[Kind][]MiddlewareFunc {
+	return s.GroupMiddlewares
+}
+
+func (s *ServerParams) GetWebSocketReadLimit() int64 {
+	return s.WebSocketReadLimit
+}
+
+func (s *ServerParams) GetWebSocketPing() time.Duration {
+	return s.WebSocketPing
+}
+
+func (s *ServerParams) GetRequestID() bool {
+	return s.RequestID
+}
+
+func (s *ServerParams) GetValidator() Validator {
+	return s.Validator
+}
+
+func (s *ServerParams) GetAdminPrefix() string {
+	return s.AdminPrefix
+}
+
+func (s *ServerParams) GetAdminAuth() MiddlewareFunc {
+	return s.AdminAuth
+}
+
+func (s *ServerParams) GetJSONSerializer() JSONSerializer {
+	return s.JSONSerializer
+}
+
+func (s *ServerParams) GetStrictQueryParams() bool {
+	return s.StrictQueryParams
+}
+
+func (s *ServerParams) GetCompressionMinLength() int {
+	return s.CompressionMinLength
+}
+
+func (s *ServerParams) GetCompressionSkipper() func(c Context) bool {
+	return s.CompressionSkipper
+}
+
+func (s *ServerParams) GetTrustedProxies() []*net.IPNet {
+	return s.TrustedProxies
+}
+
+func (s *ServerParams) GetHedging() bool {
+	return s.Hedging
+}
+
+func (s *ServerParams) GetHedgeHeader() string {
+	return s.HedgeHeader
+}
+
+func (s *ServerParams) GetListener() net.Listener {
+	return s.Listener
+}
+
+func (s *ServerParams) GetMinProtocol() string {
+	return s.MinProtocol
+}
+
+func (s *ServerParams) GetMinTLSVersion() uint16 {
+	return s.MinTLSVersion
+}
+
+func (s *ServerParams) GetJSONUseNumber() bool {
+	return s.JSONUseNumber
+}
+
+func (s *ServerParams) GetPreShutdownDelay() time.Duration {
+	return s.PreShutdownDelay
+}
+
+func (s *ServerParams) GetRequireCorrelationID() []ScopedMiddleware {
+	return s.RequireCorrelationID
+}
+
+func (s *ServerParams) GetReplayGroups() []Kind {
+	return s.ReplayGroups
 }
@@ -1,7 +1,10 @@
 package server
 
 import (
+	"os"
+	"syscall"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -38,3 +41,185 @@ func TestGettersAndSetters(t *testing.T) {
 	params.SetHost("example.com")
 	assert.Equal(t, "example.com", params.GetHost())
 }
+
+func TestWithRecentRequests(t *testing.T) {
+	params, err := newServerParams(WithRecentRequests(5))
+	assert.NoError(t, err)
+	assert.Equal(t, 5, params.GetRecentRequests())
+
+	_, err = newServerParams(WithRecentRequests(0))
+	assert.Error(t, err)
+}
+
+func TestWithMaxRoutes(t *testing.T) {
+	params, err := newServerParams(WithMaxRoutes(10))
+	assert.NoError(t, err)
+	assert.Equal(t, 10, params.GetMaxRoutes())
+
+	_, err = newServerParams(WithMaxRoutes(-1))
+	assert.Error(t, err)
+}
+
+func TestWithTimeouts(t *testing.T) {
+	params, err := newServerParams(
+		WithReadTimeout(5*time.Second),
+		WithWriteTimeout(10*time.Second),
+		WithIdleTimeout(time.Minute),
+	)
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, params.GetReadTimeout())
+	assert.Equal(t, 10*time.Second, params.GetWriteTimeout())
+	assert.Equal(t, time.Minute, params.GetIdleTimeout())
+
+	for _, opt := range []Options{WithReadTimeout(0), WithWriteTimeout(-time.Second), WithIdleTimeout(0)} {
+		_, err = newServerParams(opt)
+		assert.Error(t, err)
+	}
+}
+
+func TestWithShutdownTimeout(t *testing.T) {
+	params, err := newServerParams(WithShutdownTimeout(10 * time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 10*time.Second, params.GetShutdownTimeout())
+
+	_, err = newServerParams(WithShutdownTimeout(0))
+	assert.Error(t, err)
+}
+
+func TestWithPreShutdownDelay(t *testing.T) {
+	params, err := newServerParams(WithPreShutdownDelay(5 * time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 5*time.Second, params.GetPreShutdownDelay())
+
+	_, err = newServerParams(WithPreShutdownDelay(0))
+	assert.Error(t, err)
+}
+
+func TestWithShutdownSignals(t *testing.T) {
+	params, err := newServerParams(WithShutdownSignals(syscall.SIGQUIT))
+	assert.NoError(t, err)
+	assert.Equal(t, []os.Signal{syscall.SIGQUIT}, params.GetShutdownSignals())
+
+	_, err = newServerParams(WithShutdownSignals())
+	assert.Error(t, err)
+}
+
+func TestWithReadHeaderTimeout(t *testing.T) {
+	params, err := newServerParams(WithReadHeaderTimeout(2 * time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, 2*time.Second, params.GetReadHeaderTimeout())
+
+	_, err = newServerParams(WithReadHeaderTimeout(0))
+	assert.Error(t, err)
+}
+
+func TestWithCORS(t *testing.T) {
+	params, err := newServerParams(WithCORS([]string{"https://example.com"}, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"https://example.com"}, params.GetCORSOrigins())
+	assert.True(t, params.useCORS())
+
+	_, err = newServerParams(WithCORS(nil, nil))
+	assert.Error(t, err)
+}
+
+func TestWithCompressionEncodings(t *testing.T) {
+	params, err := newServerParams(WithCompression(5, EncodingBrotli, EncodingGzip))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"br", "gzip"}, params.GetCompressionEncoding())
+
+	_, err = newServerParams(WithCompression(5, "deflate"))
+	assert.Error(t, err)
+
+	_, err = newServerParams(WithCompression(5, EncodingGzip, EncodingGzip))
+	assert.Error(t, err)
+}
+
+func TestWithMetrics(t *testing.T) {
+	params, err := newServerParams(WithMetrics("shop"))
+	assert.NoError(t, err)
+	assert.True(t, params.GetMetrics())
+	assert.Equal(t, "shop", params.GetMetricsNamespace())
+
+	_, err = newServerParams(WithMetrics("my-shop"))
+	assert.Error(t, err)
+}
+
+func TestWithBodyLimit(t *testing.T) {
+	params, err := newServerParams(WithBodyLimit("2M"))
+	assert.NoError(t, err)
+	assert.Equal(t, "2M", params.GetBodyLimit())
+
+	_, err = NewServer(WithBodyLimit("lots"))
+	assert.ErrorContains(t, err, `invalid body limit "lots"`)
+}
+
+func TestWithGroupErrorHandler(t *testing.T) {
+	handler := func(err error, c Context) error { return err }
+
+	params, err := newServerParams(WithGroupErrorHandler(DOCS, handler))
+	assert.NoError(t, err)
+	assert.Contains(t, params.GetGroupErrorHandlers(), DOCS)
+
+	_, err = newServerParams(WithGroupErrorHandler(Kind(42), handler))
+	assert.Error(t, err)
+
+	_, err = newServerParams(WithGroupErrorHandler(API, nil))
+	assert.Error(t, err)
+}
+
+func TestWithBandwidthLimit(t *testing.T) {
+	params, err := newServerParams(WithBandwidthLimit(1024, nil))
+	assert.NoError(t, err)
+	assert.Equal(t, 1024, params.GetBandwidthLimit())
+
+	_, err = newServerParams(WithBandwidthLimit(0, nil))
+	assert.Error(t, err)
+}
+
+func TestWithLoggingProfile(t *testing.T) {
+	params, err := newServerParams(WithLoggingProfile(LoggingProd))
+	assert.NoError(t, err)
+	assert.NotNil(t, params.GetSlog())
+
+	_, err = newServerParams(WithLoggingProfile("staging"))
+	assert.Error(t, err)
+}
+
+func TestWithGroupMiddleware(t *testing.T) {
+	mw := func(next HandlerFunc) HandlerFunc { return next }
+
+	params, err := newServerParams(WithGroupMiddleware(V1, mw), WithGroupMiddleware(V1, mw))
+	assert.NoError(t, err)
+	assert.Len(t, params.GetGroupMiddlewares()[V1], 2)
+
+	_, err = newServerParams(WithGroupMiddleware(Kind(-1), mw))
+	assert.Error(t, err)
+}
+
+func TestWithWebSocketConfig(t *testing.T) {
+	params, err := newServerParams(WithWebSocketConfig(4096, 10*time.Second))
+	assert.NoError(t, err)
+	assert.Equal(t, int64(4096), params.GetWebSocketReadLimit())
+	assert.Equal(t, 10*time.Second, params.GetWebSocketPing())
+
+	_, err = newServerParams(WithWebSocketConfig(0, time.Second))
+	assert.Error(t, err)
+
+	_, err = newServerParams(WithWebSocketConfig(1024, 0))
+	assert.Error(t, err)
+}
+
+func TestWithHedging(t *testing.T) {
+	params, err := newServerParams(WithHedging("X-Request-Group"))
+	assert.NoError(t, err)
+	assert.Equal(t, "X-Request-Group", params.GetHedgeHeader())
+
+	_, err = newServerParams(WithHedging("X Hedge"))
+	assert.Error(t, err)
+}
+
+func TestWithListenerInvalid(t *testing.T) {
+	_, err := newServerParams(WithListener(nil))
+	assert.Error(t, err)
+}
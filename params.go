@@ -0,0 +1,37 @@
+package server
+
+import (
+	"net/http"
+	"net/url"
+
+	"github.com/labstack/echo/v4"
+)
+
+// unescapeParams makes route parameters always hold decoded values.
+//
+// Echo matches routes against the raw path when it carries escapes that
+// don't round-trip (such as %2F), which leaves those parameters encoded
+// while others are decoded. The policy here is to decode: "/files/a%2Fb"
+// matches "/files/:name" with name "a/b", whereas "/files/a/b" is a
+// different path.
+func unescapeParams() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if len(c.Request().URL.RawPath) == 0 {
+				return next(c)
+			}
+
+			values := c.ParamValues()
+			for i, v := range values {
+				unescaped, err := url.PathUnescape(v)
+				if err != nil {
+					return echo.NewHTTPError(http.StatusBadRequest, "invalid path parameter")
+				}
+				values[i] = unescaped
+			}
+			c.SetParamValues(values...)
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// CheckPrecondition evaluates If-Match against currentETag, or when absent
+// If-Unmodified-Since against the Last-Modified header already set on the
+// response, returning a 412 error on mismatch so writes based on a stale
+// representation are refused. If-Match uses strong comparison and an empty
+// currentETag means the resource does not exist, which only fails "*".
+func CheckPrecondition(c Context, currentETag string) error {
+	req := c.Request()
+
+	if ifMatch := req.Header.Get("If-Match"); len(ifMatch) > 0 {
+		if !etagMatches(ifMatch, currentETag) {
+			return echo.NewHTTPError(http.StatusPreconditionFailed)
+		}
+		return nil
+	}
+
+	ifUnmodified := req.Header.Get("If-Unmodified-Since")
+	lastModified := c.Response().Header().Get(echo.HeaderLastModified)
+	if len(ifUnmodified) == 0 || len(lastModified) == 0 {
+		return nil
+	}
+
+	since, err := http.ParseTime(ifUnmodified)
+	if err != nil {
+		// an invalid date is ignored as required by RFC 9110
+		return nil
+	}
+	modified, err := http.ParseTime(lastModified)
+	if err != nil {
+		return nil
+	}
+
+	if modified.Truncate(time.Second).After(since) {
+		return echo.NewHTTPError(http.StatusPreconditionFailed)
+	}
+
+	return nil
+}
+
+// etagMatches reports whether current strongly matches one of the tags in
+// an If-Match header
+func etagMatches(header, current string) bool {
+	if len(current) == 0 || strings.HasPrefix(current, "W/") {
+		return false
+	}
+
+	for _, tag := range strings.Split(header, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == current {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,63 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckPrecondition(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 15, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		header  string
+		value   string
+		etag    string
+		lastMod time.Time
+		status  int
+	}{
+		{"Matching If-Match", "If-Match", `"v2"`, `"v2"`, time.Time{}, http.StatusOK},
+		{"Stale If-Match", "If-Match", `"v1"`, `"v2"`, time.Time{}, http.StatusPreconditionFailed},
+		{"If-Match list", "If-Match", `"v1", "v2"`, `"v2"`, time.Time{}, http.StatusOK},
+		{"If-Match any", "If-Match", "*", `"v2"`, time.Time{}, http.StatusOK},
+		{"If-Match any missing resource", "If-Match", "*", "", time.Time{}, http.StatusPreconditionFailed},
+		{"Weak ETag never matches", "If-Match", `W/"v2"`, `W/"v2"`, time.Time{}, http.StatusPreconditionFailed},
+		{"Unmodified since", "If-Unmodified-Since", modified.Format(http.TimeFormat), "", modified, http.StatusOK},
+		{"Modified since", "If-Unmodified-Since", modified.Add(-time.Hour).Format(http.TimeFormat), "", modified, http.StatusPreconditionFailed},
+		{"Invalid date ignored", "If-Unmodified-Since", "yesterday", "", modified, http.StatusOK},
+		{"No precondition", "", "", `"v2"`, time.Time{}, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := NewServer()
+			rr := NewRouters()
+			rr.AddRouter("/items/1", Methods{
+				http.MethodPut: func(c Context) error {
+					if !tt.lastMod.IsZero() {
+						c.Response().Header().Set(echo.HeaderLastModified, tt.lastMod.Format(http.TimeFormat))
+					}
+					if err := CheckPrecondition(c, tt.etag); err != nil {
+						return err
+					}
+					return c.NoContent(http.StatusOK)
+				},
+			})
+			_ = server.RegisterRouters(ROOT, rr)
+
+			req := httptest.NewRequest(http.MethodPut, "/items/1", nil)
+			if len(tt.header) > 0 {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+		})
+	}
+}
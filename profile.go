@@ -0,0 +1,132 @@
+package server
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// Logging profiles for WithLoggingProfile
+const (
+	LoggingDev  = "dev"
+	LoggingProd = "prod"
+)
+
+const (
+	// sampleFirst records per message and second are always kept in prod,
+	// after that only one in sampleThereafter
+	sampleFirst      = 100
+	sampleThereafter = 100
+
+	redacted = "[REDACTED]"
+)
+
+// redactedKeys are field name fragments whose values are masked in prod
+// logs and in the admin config dump
+var redactedKeys = []string{"password", "secret", "token", "authorization", "cookie", "api_key", "api-key", "apikey"}
+
+// NewProfileLogger builds a Slog for the given profile. The dev profile
+// writes colored text with the caller at debug level; the prod profile
+// writes JSON at info level, masks sensitive fields and samples repeated
+// info and debug messages. Warnings and errors are never sampled.
+func NewProfileLogger(profile string, out io.Writer) (*slog.SugaredLogger, error) {
+	switch profile {
+	case LoggingDev:
+		sl := slog.NewSugaredLogger(out, slog.DebugLevel, func(sl *slog.SugaredLogger) {
+			sl.ReportCaller = true
+			if f, ok := sl.Formatter.(*slog.TextFormatter); ok {
+				f.EnableColor = true
+			}
+		})
+		sl.AddProcessor(slog.ProcessorFunc(showFields))
+		return sl, nil
+	case LoggingProd:
+		sl := slog.NewJSONSugared(out, slog.InfoLevel)
+		sl.SetHandlers([]slog.Handler{&sampler{next: sl, counts: map[string]int{}}})
+		sl.AddProcessor(slog.ProcessorFunc(redact))
+		return sl, nil
+	default:
+		return nil, fmt.Errorf("unknown logging profile: %q", profile)
+	}
+}
+
+// showFields copies the record fields into its data, which the text
+// formatter prints after the message
+func showFields(record *slog.Record) {
+	if len(record.Fields) == 0 {
+		return
+	}
+	if record.Data == nil {
+		record.Data = slog.M{}
+	}
+	for key, value := range record.Fields {
+		record.Data[key] = value
+	}
+}
+
+// redact masks the values of sensitive fields
+func redact(record *slog.Record) {
+	for _, fields := range []slog.M{record.Fields, record.Data} {
+		for key := range fields {
+			if sensitiveKey(key) {
+				fields[key] = redacted
+			}
+		}
+	}
+}
+
+// sensitiveKey reports whether a field name suggests a secret value
+func sensitiveKey(key string) bool {
+	lower := strings.ToLower(key)
+	for _, fragment := range redactedKeys {
+		if strings.Contains(lower, fragment) {
+			return true
+		}
+	}
+	return false
+}
+
+// sampler keeps the first records of each message per second and then one
+// in sampleThereafter, forwarding them to the sugared logger it wraps
+type sampler struct {
+	next *slog.SugaredLogger
+
+	mu     sync.Mutex
+	window time.Time
+	counts map[string]int
+}
+
+func (s *sampler) Handle(record *slog.Record) error {
+	if record.Level <= slog.WarnLevel || s.keep(record.Message, record.Time) {
+		return s.next.Handle(record)
+	}
+	return nil
+}
+
+// keep counts the message in the current one second window
+func (s *sampler) keep(msg string, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.window) >= time.Second {
+		s.window = now
+		clear(s.counts)
+	}
+
+	s.counts[msg]++
+	n := s.counts[msg]
+	return n <= sampleFirst || (n-sampleFirst)%sampleThereafter == 0
+}
+
+func (s *sampler) IsHandling(level slog.Level) bool {
+	return s.next.IsHandling(level)
+}
+
+// Flush and Close are no-ops: the sugared logger writes straight to its
+// output and closing it would visit this handler again
+func (s *sampler) Flush() error { return nil }
+func (s *sampler) Close() error { return nil }
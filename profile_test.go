@@ -0,0 +1,69 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLoggingProfileDev(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewProfileLogger(LoggingDev, &buf)
+	assert.NoError(t, err)
+
+	logger.WithFields(slog.M{"path": "/users"}).Debug("handler failed")
+	assert.NoError(t, logger.Flush())
+
+	out := buf.String()
+	assert.Contains(t, out, "handler failed")
+	assert.Contains(t, out, "/users")
+	assert.Contains(t, out, "profile_test.go")
+	assert.False(t, json.Valid(bytes.TrimSpace(buf.Bytes())), out)
+}
+
+func TestLoggingProfileProd(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := NewProfileLogger(LoggingProd, &buf)
+	assert.NoError(t, err)
+
+	logger.Debug("dropped below info")
+	logger.WithFields(slog.M{"user": "bob", "password": "hunter2", "Authorization": "Bearer abc"}).Info("login")
+	for i := 0; i < sampleFirst+sampleThereafter; i++ {
+		logger.Info("busy")
+	}
+	for i := 0; i < sampleFirst+1; i++ {
+		logger.Warn("warning")
+	}
+	assert.NoError(t, logger.Flush())
+
+	counts := map[string]int{}
+	var login map[string]any
+	scanner := bufio.NewScanner(&buf)
+	for scanner.Scan() {
+		var record map[string]any
+		if !assert.NoError(t, json.Unmarshal(scanner.Bytes(), &record), scanner.Text()) {
+			continue
+		}
+		msg := record["message"].(string)
+		counts[msg]++
+		if msg == "login" {
+			login = record
+		}
+	}
+
+	assert.Zero(t, counts["dropped below info"])
+	assert.Equal(t, sampleFirst+1, counts["busy"])
+	assert.Equal(t, sampleFirst+1, counts["warning"])
+
+	if assert.NotNil(t, login) {
+		assert.Equal(t, "bob", login["user"])
+		assert.Equal(t, redacted, login["password"])
+		assert.Equal(t, redacted, login["Authorization"])
+		assert.False(t, strings.Contains(buf.String(), "hunter2"))
+	}
+}
@@ -0,0 +1,23 @@
+package server
+
+import (
+	"net"
+
+	"github.com/labstack/echo/v4"
+)
+
+// trustedProxiesExtractor derives the client IP from X-Forwarded-For,
+// walking it from the nearest hop and skipping the addresses in proxies.
+// Only those ranges are trusted, not loopback or private networks, so a
+// client connecting directly can't spoof its IP with the header.
+func trustedProxiesExtractor(proxies []*net.IPNet) echo.IPExtractor {
+	opts := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+	for _, ipnet := range proxies {
+		opts = append(opts, echo.TrustIPRange(ipnet))
+	}
+	return echo.ExtractIPFromXFFHeader(opts...)
+}
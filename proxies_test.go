@@ -0,0 +1,65 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithTrustedProxies(t *testing.T) {
+	server, err := NewServer(WithTrustedProxies("10.0.0.0/8", "2001:db8::/32"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/ip", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.RealIP())
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name       string
+		remoteAddr string
+		xff        string
+		expectedIP string
+	}{
+		{"through proxy", "10.0.0.5:4000", "203.0.113.7", "203.0.113.7"},
+		{"through proxy chain", "10.0.0.5:4000", "203.0.113.7, 10.1.2.3", "203.0.113.7"},
+		{"spoofed prefix", "10.0.0.5:4000", "1.2.3.4, 203.0.113.7", "203.0.113.7"},
+		{"ipv6 proxy", "[2001:db8::1]:4000", "203.0.113.7", "203.0.113.7"},
+		{"untrusted source", "198.51.100.9:4000", "203.0.113.7", "198.51.100.9"},
+		{"private but untrusted", "192.168.1.10:4000", "203.0.113.7", "192.168.1.10"},
+		{"no header", "10.0.0.5:4000", "", "10.0.0.5"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/ip", nil)
+			req.RemoteAddr = tt.remoteAddr
+			if len(tt.xff) > 0 {
+				req.Header.Set(echo.HeaderXForwardedFor, tt.xff)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedIP, rec.Body.String())
+		})
+	}
+}
+
+func TestWithTrustedProxiesInvalid(t *testing.T) {
+	_, err := NewServer(WithTrustedProxies("10.0.0.0/33"))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithTrustedProxies("10.0.0.1"))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithTrustedProxies())
+	assert.Error(t, err)
+}
@@ -0,0 +1,95 @@
+package server
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// RequestRecord holds the metadata of a served request
+type RequestRecord struct {
+	Method  string        `json:"method"`
+	Path    string        `json:"path"`
+	Status  int           `json:"status"`
+	Latency time.Duration `json:"latency"`
+	Time    time.Time     `json:"time"`
+}
+
+// requestRing is a fixed size ring buffer of request records
+type requestRing struct {
+	mu      sync.Mutex
+	records []RequestRecord
+	next    int
+	full    bool
+}
+
+func newRequestRing(size int) *requestRing {
+	return &requestRing{records: make([]RequestRecord, size)}
+}
+
+// add stores a record, overwriting the oldest one when the buffer is full
+func (r *requestRing) add(rec RequestRecord) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// list returns the stored records from oldest to newest
+func (r *requestRing) list() []RequestRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.full {
+		return append([]RequestRecord(nil), r.records[:r.next]...)
+	}
+
+	out := make([]RequestRecord, 0, len(r.records))
+	out = append(out, r.records[r.next:]...)
+	return append(out, r.records[:r.next]...)
+}
+
+// recordRequests stores the metadata of every request in the ring buffer
+func (s *Server) recordRequests() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			s.recent.add(RequestRecord{
+				Method:  c.Request().Method,
+				Path:    c.Request().URL.Path,
+				Status:  c.Response().Status,
+				Latency: time.Since(start),
+				Time:    start,
+			})
+
+			return err
+		}
+	}
+}
+
+// RecentRequests returns the last requests served, oldest first. It returns
+// nil unless the server was created with WithRecentRequests.
+func (s *Server) RecentRequests() []RequestRecord {
+	if s.recent == nil {
+		return nil
+	}
+	return s.recent.list()
+}
+
+// RecentRequestsHandler returns a handler exposing RecentRequests as JSON,
+// meant to be registered on an admin route
+func (s *Server) RecentRequestsHandler() HandlerFunc {
+	return func(c Context) error {
+		return c.JSON(http.StatusOK, s.RecentRequests())
+	}
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecentRequests(t *testing.T) {
+	server, _ := NewServer(WithRecentRequests(3))
+	rr := NewRouters()
+	rr.AddRouter("/test/:id", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.Param("id"))
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+	for _, path := range []string{"/test/1", "/test/2", "/test/3", "/missing", "/test/5"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+	}
+
+	records := server.RecentRequests()
+	if assert.Len(t, records, 3) {
+		assert.Equal(t, "/test/3", records[0].Path)
+		assert.Equal(t, http.StatusOK, records[0].Status)
+		assert.Equal(t, "/missing", records[1].Path)
+		assert.Equal(t, http.StatusNotFound, records[1].Status)
+		assert.Equal(t, "/test/5", records[2].Path)
+		assert.Equal(t, http.MethodGet, records[2].Method)
+	}
+}
+
+func TestRecentRequestsHandler(t *testing.T) {
+	server, _ := NewServer(WithRecentRequests(10))
+	rr := NewRouters()
+	rr.AddRouter("/admin/requests", Methods{
+		http.MethodGet: server.RecentRequestsHandler(),
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+	for i := 0; i < 2; i++ {
+		req := httptest.NewRequest(http.MethodGet, "/admin/requests", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var records []RequestRecord
+		assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+		assert.Len(t, records, i)
+	}
+}
+
+func TestRecentRequestsDisabled(t *testing.T) {
+	server, _ := NewServer()
+	assert.Nil(t, server.RecentRequests())
+}
@@ -0,0 +1,32 @@
+package server
+
+import (
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// recoverPanics turns handler panics into 500 responses, logging the panic
+// with the stack trace of the panicking goroutine. It is the innermost
+// built-in middleware, so the access log, metrics and recent requests see
+// the resulting 500, and the route, request ID and trace ID resolved by the
+// outer middlewares are logged too.
+func (s *Server) recoverPanics() MiddlewareFunc {
+	return middleware.RecoverWithConfig(middleware.RecoverConfig{
+		DisableStackAll: true,
+		LogErrorFunc: func(c Context, err error, stack []byte) error {
+			fields := slog.M{
+				"error":      err.Error(),
+				"method":     c.Request().Method,
+				"path":       c.Request().URL.Path,
+				"route":      c.Path(),
+				"request_id": logRequestID(c),
+				"stack":      string(stack),
+			}
+			if traceID := TraceID(c); len(traceID) > 0 {
+				fields["trace_id"] = traceID
+			}
+			s.log(slog.ErrorLevel, "panic recovered", fields)
+			return err
+		},
+	})
+}
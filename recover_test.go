@@ -0,0 +1,133 @@
+package server
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// panicRecord returns the last panic record in the JSON log written to buf
+func panicRecord(t *testing.T, buf *bytes.Buffer) map[string]any {
+	var record map[string]any
+	scanner := bufio.NewScanner(buf)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		var r map[string]any
+		if assert.NoError(t, json.Unmarshal(scanner.Bytes(), &r)) && r["message"] == "panic recovered" {
+			record = r
+		}
+	}
+	return record
+}
+
+func TestWithRecover(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithRecover(true))
+	middlewares := server.Middlewares()
+	assert.Equal(t, "recover", middlewares[len(middlewares)-1])
+
+	rr := NewRouters()
+	_ = rr.AddRouter("/panic", Methods{
+		http.MethodGet: func(c Context) error {
+			panic("something went wrong")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/panic", nil)
+	rec := httptest.NewRecorder()
+	assert.NotPanics(t, func() { server.GetEcho().ServeHTTP(rec, req) })
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"message":"Internal Server Error"}`, rec.Body.String())
+
+	assert.NoError(t, logger.Flush())
+
+	record := panicRecord(t, &buf)
+	if assert.NotNil(t, record) {
+		assert.Equal(t, "ERROR", record["level"])
+		assert.Equal(t, "something went wrong", record["error"])
+		assert.Equal(t, "/panic", record["path"])
+		assert.Contains(t, record["stack"], "recover_test.go")
+	}
+}
+
+func TestRecoverLogsRequestContext(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithRecover(true), WithRequestID())
+	rr := NewRouters()
+	_ = rr.AddRouter("/users/:id", Methods{
+		http.MethodGet: func(c Context) error {
+			panic("nil user")
+		},
+	})
+	_ = server.RegisterRouters(V1, rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users/42", nil)
+	req.Header.Set(echo.HeaderXRequestID, "req-42")
+	req.Header.Set(HeaderTraceParent, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, "req-42", rec.Header().Get(echo.HeaderXRequestID))
+	assert.NoError(t, logger.Flush())
+
+	record := panicRecord(t, &buf)
+	if assert.NotNil(t, record) {
+		assert.Equal(t, "req-42", record["request_id"])
+		assert.Equal(t, "/v1/users/:id", record["route"])
+		assert.Equal(t, "/v1/users/42", record["path"])
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", record["trace_id"])
+	}
+}
+
+func TestRecoveredPanicIsLoggedAndMeasured(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithRecover(true), WithAccessLog(), WithMetrics("shop"))
+	rr := NewRouters()
+	_ = rr.AddRouter("/panic", Methods{
+		http.MethodGet: func(c Context) error {
+			panic("something went wrong")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+	assert.NoError(t, server.RegisterMetricsEndpoint("/metrics"))
+
+	e := server.GetEcho()
+	rec := httptest.NewRecorder()
+	assert.NotPanics(t, func() { e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/panic", nil)) })
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	assert.NoError(t, logger.Flush())
+	records := accessLogRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "/panic", records[0]["path"])
+		assert.Equal(t, float64(http.StatusInternalServerError), records[0]["status"])
+	}
+
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	assert.Contains(t, rec.Body.String(), `shop_http_requests_total{method="GET",route="/panic",status="500"} 1`)
+}
+
+func TestWithoutRecover(t *testing.T) {
+	server, _ := NewServer()
+	assert.NotContains(t, server.Middlewares(), "recover")
+
+	server, _ = NewServer(WithRecover(true), WithRecover(false))
+	assert.NotContains(t, server.Middlewares(), "recover")
+}
@@ -0,0 +1,90 @@
+package server
+
+import (
+	"fmt"
+	"sync"
+)
+
+// HandlerRegistry holds handlers by name so route definitions can refer to
+// them indirectly. Handlers are resolved on every request, which lets tests
+// swap an implementation, e.g. for a mock, after the routes are registered.
+type HandlerRegistry struct {
+	mu       sync.RWMutex
+	handlers map[string]HandlerFunc
+}
+
+// NewHandlerRegistry creates an empty handler registry
+func NewHandlerRegistry() *HandlerRegistry {
+	return &HandlerRegistry{handlers: map[string]HandlerFunc{}}
+}
+
+// Register adds a handler under name, failing if the name is already taken
+func (h *HandlerRegistry) Register(name string, handler HandlerFunc) error {
+	if len(name) == 0 || handler == nil {
+		return fmt.Errorf("handler registry needs a name and a handler")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if _, ok := h.handlers[name]; ok {
+		return fmt.Errorf("handler %q already registered", name)
+	}
+	h.handlers[name] = handler
+
+	return nil
+}
+
+// Swap replaces the handler registered under name and returns the previous
+// one, failing if the name is unknown
+func (h *HandlerRegistry) Swap(name string, handler HandlerFunc) (HandlerFunc, error) {
+	if handler == nil {
+		return nil, fmt.Errorf("handler registry needs a handler")
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	old, ok := h.handlers[name]
+	if !ok {
+		return nil, fmt.Errorf("handler %q not registered", name)
+	}
+	h.handlers[name] = handler
+
+	return old, nil
+}
+
+// Lookup returns the handler currently registered under name
+func (h *HandlerRegistry) Lookup(name string) (HandlerFunc, bool) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	handler, ok := h.handlers[name]
+	return handler, ok
+}
+
+// Handler returns a handler dispatching to whatever is registered under
+// name at request time. Unknown names answer with a 500.
+func (h *HandlerRegistry) Handler(name string) HandlerFunc {
+	return func(c Context) error {
+		handler, ok := h.Lookup(name)
+		if !ok {
+			return fmt.Errorf("handler %q not registered", name)
+		}
+		return handler(c)
+	}
+}
+
+// AddNamedRouter adds a router whose methods map to handler names in reg,
+// failing if a name is not registered yet or the route is a duplicate
+func (r *RegisterRouters) AddNamedRouter(reg *HandlerRegistry, path string, names map[string]string) error {
+	methods := make(Methods, len(names))
+	for method, name := range names {
+		if _, ok := reg.Lookup(name); !ok {
+			return fmt.Errorf("handler %q not registered", name)
+		}
+		methods[method] = reg.Handler(name)
+	}
+
+	return r.AddRouter(path, methods)
+}
@@ -0,0 +1,50 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHandlerRegistrySwap(t *testing.T) {
+	reg := NewHandlerRegistry()
+	assert.NoError(t, reg.Register("users.get", func(c Context) error {
+		return c.String(http.StatusOK, "real")
+	}))
+	assert.Error(t, reg.Register("users.get", func(c Context) error { return nil }))
+
+	rr := NewRouters()
+	assert.NoError(t, rr.AddNamedRouter(reg, "/users", map[string]string{
+		http.MethodGet: "users.get",
+	}))
+	assert.Error(t, rr.AddNamedRouter(reg, "/orders", map[string]string{
+		http.MethodGet: "orders.get",
+	}))
+
+	server, _ := NewServer()
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	get := func() *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users", nil))
+		return rec
+	}
+
+	assert.Equal(t, "real", get().Body.String())
+
+	called := false
+	_, err := reg.Swap("users.get", func(c Context) error {
+		called = true
+		return c.String(http.StatusOK, "mock")
+	})
+	assert.NoError(t, err)
+
+	rec := get()
+	assert.True(t, called)
+	assert.Equal(t, "mock", rec.Body.String())
+
+	_, err = reg.Swap("orders.get", func(c Context) error { return nil })
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package server
+
+import (
+	"github.com/labstack/echo/v4"
+)
+
+// recordReplay remembers a registration so ResetRouters can run it again
+// on a fresh echo instance
+func (s *Server) recordReplay(fn func() error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.replays = append(s.replays, fn)
+}
+
+// forwardReloaded hands requests to the echo instance built by the last
+// ResetRouters. It runs first on the instance owning the listeners, which
+// keeps serving, so none of its own middlewares run for forwarded requests.
+func (s *Server) forwardReloaded() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if e := s.live.Load(); e != s.front {
+				e.ServeHTTP(c.Response().Writer, c.Request())
+				return nil
+			}
+			return next(c)
+		}
+	}
+}
+
+// routeState is the registration state ResetRouters rebuilds, kept aside to
+// roll back to when the rebuild fails
+type routeState struct {
+	echo          *echo.Echo
+	routes        int
+	registry      []registeredRoute
+	invalid       []error
+	noCompression map[string]bool
+	builtin       map[string]bool
+	groups        map[Kind]*echo.Group
+	rootScoped    []MiddlewareFunc
+	rootGroup     *echo.Group
+	middlewares   []string
+}
+
+// swapRouteState installs next as the registration state and returns the
+// previous one
+func (s *Server) swapRouteState(next routeState) routeState {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	prev := routeState{
+		echo:          s.echo,
+		routes:        s.routes,
+		registry:      s.registry,
+		invalid:       s.invalid,
+		noCompression: s.noCompression,
+		builtin:       s.builtin,
+		groups:        s.groups,
+		rootScoped:    s.rootScoped,
+		rootGroup:     s.rootGroup,
+		middlewares:   s.middlewares,
+	}
+
+	s.echo = next.echo
+	s.routes = next.routes
+	s.registry = next.registry
+	s.invalid = next.invalid
+	s.noCompression = next.noCompression
+	s.builtin = next.builtin
+	s.groups = next.groups
+	s.rootScoped = next.rootScoped
+	s.rootGroup = next.rootGroup
+	s.middlewares = next.middlewares
+
+	return prev
+}
+
+// ResetRouters rebuilds the route table for a watch-and-reload development
+// loop. A fresh echo instance gets the built-in middlewares, then the Use
+// and RegisterRouters calls made so far are replayed in order, reading the
+// current content of their RegisterRouters, so routers added or changed
+// since show up. A running server switches to the new table for the
+// following requests without closing its listener. When a replay fails the
+// previous table is kept and the error returned.
+//
+// Routes added directly on GetEcho() and groups obtained with Group are
+// not carried over. It must not run concurrently with other registrations.
+func (s *Server) ResetRouters() error {
+	s.mu.RLock()
+	replays := append([]func() error(nil), s.replays...)
+	s.mu.RUnlock()
+
+	prev := s.swapRouteState(routeState{
+		echo:          s.newEcho(),
+		noCompression: make(map[string]bool),
+		builtin:       make(map[string]bool),
+		groups:        make(map[Kind]*echo.Group),
+	})
+
+	err := s.install()
+	for _, replay := range replays {
+		if err != nil {
+			break
+		}
+		err = replay()
+	}
+	if err != nil {
+		s.swapRouteState(prev)
+		return err
+	}
+
+	s.live.Store(s.echo)
+	return nil
+}
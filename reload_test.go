@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestResetRouters(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+	server.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Global", "yes")
+			return next(c)
+		}
+	})
+
+	rr := NewRouters()
+	rr.AddRouter("/hello", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "hello")
+		},
+	})
+	group := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Header().Set("X-Group", "yes")
+			return next(c)
+		}
+	}
+	assert.NoError(t, server.RegisterRouters(ROOT, rr, group))
+	middlewares := server.Middlewares()
+
+	server.Start()
+	<-server.Listening()
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+	}()
+
+	get := func(path string) (int, string, http.Header) {
+		resp, err := http.Get("http://" + server.Addr() + path)
+		if !assert.NoError(t, err) {
+			return 0, "", nil
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return resp.StatusCode, string(body), resp.Header
+	}
+
+	code, _, _ := get("/bye")
+	assert.Equal(t, http.StatusNotFound, code)
+
+	rr.Routers[0].Methods[http.MethodGet] = func(c Context) error {
+		return c.String(http.StatusOK, "hello again")
+	}
+	rr.AddRouter("/bye", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "bye")
+		},
+	})
+	assert.NoError(t, server.ResetRouters())
+	assert.Equal(t, middlewares, server.Middlewares())
+
+	code, body, header := get("/hello")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "hello again", body)
+	assert.Equal(t, "yes", header.Get("X-Global"))
+	assert.Equal(t, "yes", header.Get("X-Group"))
+
+	code, body, _ = get("/bye")
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "bye", body)
+
+	req := httptest.NewRequest(http.MethodGet, "/bye", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+	assert.Equal(t, "bye", rec.Body.String())
+}
+
+func TestResetRoutersRollback(t *testing.T) {
+	server, _ := NewServer(WithMaxRoutes(2))
+	ok := func(c Context) error { return c.String(http.StatusOK, c.Path()) }
+
+	rr := NewRouters()
+	rr.AddRouter("/a", Methods{http.MethodGet: ok})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+	e := server.GetEcho()
+
+	rr.AddRouter("/b", Methods{http.MethodGet: ok})
+	rr.AddRouter("/c", Methods{http.MethodGet: ok})
+	assert.Error(t, server.ResetRouters())
+	assert.Same(t, e, server.GetEcho())
+	assert.Len(t, server.ListRoutes(), 1)
+
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/a", nil))
+	assert.Equal(t, http.StatusOK, rec.Code)
+}
+
+func TestResetRoutersWhileServing(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/ping", Methods{
+		http.MethodGet: func(c Context) error {
+			server.log(slog.InfoLevel, "ping", nil)
+			return c.String(http.StatusOK, "pong")
+		},
+	})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	done := make(chan []int)
+	go func() {
+		codes := make([]int, 0, 100)
+		for i := 0; i < 100; i++ {
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/ping", nil))
+			codes = append(codes, rec.Code)
+			_ = server.RouteTable()
+		}
+		done <- codes
+	}()
+
+	for {
+		assert.NoError(t, server.ResetRouters())
+		select {
+		case codes := <-done:
+			for _, code := range codes {
+				assert.Equal(t, http.StatusOK, code)
+			}
+			return
+		default:
+		}
+	}
+}
@@ -0,0 +1,134 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/gookit/slog"
+)
+
+// maxRecordedBody caps the request body bytes WithRequestRecorder saves
+const maxRecordedBody = 1 << 20
+
+// RecordedRequest is a request serialized by WithRequestRecorder.
+// Truncated is set when the body was longer than the recorded part.
+type RecordedRequest struct {
+	Method    string      `json:"method"`
+	URI       string      `json:"uri"`
+	Host      string      `json:"host"`
+	Header    http.Header `json:"header"`
+	Body      []byte      `json:"body,omitempty"`
+	Truncated bool        `json:"truncated,omitempty"`
+	Time      time.Time   `json:"time"`
+}
+
+// recordedBody is a request body whose first bytes were read for recording,
+// served again ahead of the rest
+type recordedBody struct {
+	io.Reader
+	io.Closer
+}
+
+// recordToDisk writes every incoming request to its own JSON file in dir.
+// Headers carrying credentials such as Authorization and Cookie are
+// redacted and at most maxRecordedBody bytes of the body are saved, the
+// handler still receiving all of it. Failing to record is logged and never
+// fails the request.
+func (s *Server) recordToDisk(dir string) MiddlewareFunc {
+	var seq atomic.Uint64
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			req := c.Request()
+
+			var (
+				body      []byte
+				truncated bool
+			)
+			if req.Body != nil {
+				var err error
+				if body, err = io.ReadAll(io.LimitReader(req.Body, maxRecordedBody+1)); err != nil {
+					return err
+				}
+				req.Body = recordedBody{io.MultiReader(bytes.NewReader(body), req.Body), req.Body}
+				if len(body) > maxRecordedBody {
+					body, truncated = body[:maxRecordedBody], true
+				}
+			}
+
+			rec := RecordedRequest{
+				Method:    req.Method,
+				URI:       req.RequestURI,
+				Host:      req.Host,
+				Header:    redactHeader(req.Header),
+				Body:      body,
+				Truncated: truncated,
+				Time:      time.Now(),
+			}
+
+			name := fmt.Sprintf("%d-%06d.json", rec.Time.UnixNano(), seq.Add(1))
+			if err := writeRecordedRequest(filepath.Join(dir, name), rec); err != nil {
+				s.log(slog.WarnLevel, "request not recorded", slog.M{
+					"method": req.Method,
+					"path":   req.URL.Path,
+					"error":  err.Error(),
+				})
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// redactHeader returns a copy of h with the values of sensitive headers
+// masked
+func redactHeader(h http.Header) http.Header {
+	out := h.Clone()
+	for key := range out {
+		if sensitiveKey(key) {
+			out[key] = []string{redacted}
+		}
+	}
+	return out
+}
+
+func writeRecordedRequest(file string, rec RecordedRequest) error {
+	data, err := json.MarshalIndent(rec, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(file, data, 0o600)
+}
+
+// ReplayRequest feeds a request recorded by WithRequestRecorder back
+// through the server and returns the response
+func (s *Server) ReplayRequest(file string) (*http.Response, error) {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var rec RecordedRequest
+	if err := json.Unmarshal(data, &rec); err != nil {
+		return nil, fmt.Errorf("invalid recorded request %s: %w", file, err)
+	}
+
+	req := httptest.NewRequest(rec.Method, rec.URI, bytes.NewReader(rec.Body))
+	req.Host = rec.Host
+	for key, values := range rec.Header {
+		req.Header[key] = values
+	}
+
+	w := httptest.NewRecorder()
+	s.live.Load().ServeHTTP(w, req)
+
+	return w.Result(), nil
+}
@@ -0,0 +1,123 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestRecorderReplay(t *testing.T) {
+	dir := t.TempDir()
+
+	server, err := NewServer(WithRequestRecorder(dir))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/echo", Methods{
+		http.MethodPost: func(c Context) error {
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, c.Request().Header.Get("X-Tenant")+":"+c.QueryParam("q")+":"+string(body))
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodPost, "/echo?q=search", strings.NewReader("payload"))
+	req.Header.Set("X-Tenant", "acme")
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "acme:search:payload", rec.Body.String())
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	assert.NoError(t, err)
+	if !assert.Len(t, files, 1) {
+		return
+	}
+
+	replay, _ := NewServer()
+	_ = replay.RegisterRouters(ROOT, rr)
+
+	res, err := replay.ReplayRequest(files[0])
+	if assert.NoError(t, err) {
+		defer res.Body.Close()
+		body, _ := io.ReadAll(res.Body)
+		assert.Equal(t, rec.Code, res.StatusCode)
+		assert.Equal(t, rec.Body.String(), string(body))
+	}
+}
+
+func TestRequestRecorderRedactsAndCaps(t *testing.T) {
+	dir := t.TempDir()
+	server, _ := NewServer(WithRequestRecorder(dir))
+
+	rr := NewRouters()
+	rr.AddRouter("/upload", Methods{
+		http.MethodPost: func(c Context) error {
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return err
+			}
+			return c.String(http.StatusOK, strconv.Itoa(len(body)))
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	payload := strings.Repeat("x", maxRecordedBody+10)
+	req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(payload))
+	req.Header.Set("Authorization", "Bearer secret")
+	req.Header.Set("Cookie", "session=secret")
+	req.Header.Set("X-Api-Key", "secret")
+	req.Header.Set("X-Tenant", "acme")
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	// the handler still reads the whole body
+	assert.Equal(t, strconv.Itoa(len(payload)), rec.Body.String())
+
+	files, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+	if !assert.Len(t, files, 1) {
+		return
+	}
+	data, err := os.ReadFile(files[0])
+	assert.NoError(t, err)
+	assert.NotContains(t, string(data), "secret")
+
+	var recorded RecordedRequest
+	if assert.NoError(t, json.Unmarshal(data, &recorded)) {
+		assert.Equal(t, []string{redacted}, recorded.Header["Authorization"])
+		assert.Equal(t, []string{redacted}, recorded.Header["Cookie"])
+		assert.Equal(t, []string{redacted}, recorded.Header["X-Api-Key"])
+		assert.Equal(t, []string{"acme"}, recorded.Header["X-Tenant"])
+		assert.Len(t, recorded.Body, maxRecordedBody)
+		assert.True(t, recorded.Truncated)
+	}
+}
+
+func TestReplayRequestInvalidFile(t *testing.T) {
+	server, _ := NewServer()
+
+	_, err := server.ReplayRequest(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+
+	file := filepath.Join(t.TempDir(), "broken.json")
+	assert.NoError(t, os.WriteFile(file, []byte("not json"), 0o600))
+	_, err = server.ReplayRequest(file)
+	assert.Error(t, err)
+}
+
+func TestWithRequestRecorderInvalidDir(t *testing.T) {
+	_, err := NewServer(WithRequestRecorder(filepath.Join(t.TempDir(), "missing")))
+	assert.Error(t, err)
+}
@@ -0,0 +1,119 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// ReplayStore remembers single-use request IDs
+type ReplayStore interface {
+	// Seen records id and reports whether it was already recorded within
+	// the store's TTL. The TTL runs from the first time id was recorded.
+	Seen(ctx context.Context, id string) (bool, error)
+
+	// Forget removes id so a request that failed can be retried with it
+	Forget(ctx context.Context, id string) error
+}
+
+// memoryReplayStore is an in-process ReplayStore
+type memoryReplayStore struct {
+	ttl time.Duration
+
+	mu        sync.Mutex
+	expires   map[string]time.Time
+	lastPrune time.Time
+}
+
+// NewMemoryReplayStore returns a ReplayStore keeping IDs in memory for ttl.
+// Use a shared store such as Redis when running several instances.
+func NewMemoryReplayStore(ttl time.Duration) ReplayStore {
+	return &memoryReplayStore{ttl: ttl, expires: make(map[string]time.Time)}
+}
+
+func (m *memoryReplayStore) Seen(_ context.Context, id string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(m.lastPrune) > m.ttl {
+		for k, exp := range m.expires {
+			if !now.Before(exp) {
+				delete(m.expires, k)
+			}
+		}
+		m.lastPrune = now
+	}
+
+	// a replay keeps the expiry set on first sight
+	if exp, ok := m.expires[id]; ok && now.Before(exp) {
+		return true, nil
+	}
+	m.expires[id] = now.Add(m.ttl)
+
+	return false, nil
+}
+
+func (m *memoryReplayStore) Forget(_ context.Context, id string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	delete(m.expires, id)
+	return nil
+}
+
+// rejectReplays answers 400 to requests without an ID and 409 to requests
+// whose ID was already used. Built-in routes such as health checks are
+// skipped, and the ID of a request failing with a 5xx is forgotten so the
+// client can retry it.
+func (s *Server) rejectReplays() MiddlewareFunc {
+	store, header := s.params.GetReplayStore(), s.params.GetReplayHeader()
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if s.builtinRoute(c) {
+				return next(c)
+			}
+
+			id := c.Request().Header.Get(header)
+			if len(id) == 0 {
+				return echo.NewHTTPError(http.StatusBadRequest, "missing "+header+" header")
+			}
+
+			ctx := c.Request().Context()
+			seen, err := store.Seen(ctx, id)
+			if err != nil {
+				return err
+			}
+			if seen {
+				return echo.NewHTTPError(http.StatusConflict, "request already processed")
+			}
+
+			err = next(c)
+
+			status := c.Response().Status
+			if err != nil && !c.Response().Committed {
+				status = http.StatusInternalServerError
+				var he *echo.HTTPError
+				if errors.As(err, &he) {
+					status = he.Code
+				}
+			}
+			if status >= http.StatusInternalServerError {
+				if ferr := store.Forget(context.WithoutCancel(ctx), id); ferr != nil {
+					s.log(slog.ErrorLevel, "replay store forget failed", slog.M{
+						"error": ferr.Error(),
+						"id":    id,
+					})
+				}
+			}
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,200 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingReplayStore struct{}
+
+func (failingReplayStore) Seen(context.Context, string) (bool, error) {
+	return false, errors.New("store unavailable")
+}
+
+func (failingReplayStore) Forget(context.Context, string) error {
+	return errors.New("store unavailable")
+}
+
+func TestWithReplayProtection(t *testing.T) {
+	server, err := NewServer(WithReplayProtection(NewMemoryReplayStore(time.Minute), ""))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/transfer", Methods{
+		http.MethodPost: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name         string
+		id           string
+		expectedCode int
+	}{
+		{"First use", "req-1", http.StatusOK},
+		{"Replay", "req-1", http.StatusConflict},
+		{"Other ID", "req-2", http.StatusOK},
+		{"Missing ID", "", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+			if len(tt.id) > 0 {
+				req.Header.Set(echo.HeaderXRequestID, tt.id)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestWithReplayProtectionStoreError(t *testing.T) {
+	server, _ := NewServer(WithReplayProtection(failingReplayStore{}, "X-Nonce"))
+
+	rr := NewRouters()
+	rr.AddRouter("/transfer", Methods{
+		http.MethodPost: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+	req.Header.Set("X-Nonce", "n-1")
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+}
+
+func TestMemoryReplayStoreTTL(t *testing.T) {
+	store := NewMemoryReplayStore(20 * time.Millisecond)
+
+	seen, _ := store.Seen(context.Background(), "id")
+	assert.False(t, seen)
+
+	seen, _ = store.Seen(context.Background(), "id")
+	assert.True(t, seen)
+
+	time.Sleep(40 * time.Millisecond)
+
+	seen, _ = store.Seen(context.Background(), "id")
+	assert.False(t, seen)
+}
+
+func TestMemoryReplayStoreFixedExpiry(t *testing.T) {
+	store := NewMemoryReplayStore(100 * time.Millisecond)
+
+	seen, _ := store.Seen(context.Background(), "id")
+	assert.False(t, seen)
+
+	time.Sleep(60 * time.Millisecond)
+
+	seen, _ = store.Seen(context.Background(), "id")
+	assert.True(t, seen)
+
+	// the replay above must not push the expiry back
+	time.Sleep(60 * time.Millisecond)
+
+	seen, _ = store.Seen(context.Background(), "id")
+	assert.False(t, seen)
+}
+
+func TestWithReplayProtectionRetryAfterServerError(t *testing.T) {
+	server, err := NewServer(WithReplayProtection(NewMemoryReplayStore(time.Minute), ""))
+	assert.NoError(t, err)
+
+	fail := true
+	rr := NewRouters()
+	rr.AddRouter("/transfer", Methods{
+		http.MethodPost: func(c Context) error {
+			if fail {
+				return echo.NewHTTPError(http.StatusBadGateway, "upstream down")
+			}
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	send := func() int {
+		req := httptest.NewRequest(http.MethodPost, "/transfer", nil)
+		req.Header.Set(echo.HeaderXRequestID, "req-1")
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusBadGateway, send())
+
+	fail = false
+	assert.Equal(t, http.StatusOK, send())
+	assert.Equal(t, http.StatusConflict, send())
+}
+
+func TestWithReplayProtectionSkipsBuiltinRoutes(t *testing.T) {
+	server, err := NewServer(
+		WithReplayProtection(NewMemoryReplayStore(time.Minute), ""),
+		WithMetrics("shop"),
+	)
+	assert.NoError(t, err)
+	assert.NoError(t, server.RegisterHealthChecks(HealthOptions{}))
+	assert.NoError(t, server.RegisterMetricsEndpoint("/metrics"))
+
+	for _, path := range []string{"/healthz", "/healthz", "/metrics", "/metrics"} {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusOK, rec.Code, path)
+	}
+}
+
+func TestWithReplayProtectionForGroups(t *testing.T) {
+	server, err := NewServer(WithReplayProtection(NewMemoryReplayStore(time.Minute), "", ForGroups(V1)))
+	assert.NoError(t, err)
+	assert.NotContains(t, server.Middlewares(), "replay-protection")
+
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, "test passed")
+	}
+	for _, group := range []Kind{ROOT, V1} {
+		rr := NewRouters()
+		rr.AddRouter("/transfer", Methods{http.MethodPost: handler})
+		assert.NoError(t, server.RegisterRouters(group, rr))
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{"Unscoped group", "/transfer", http.StatusOK},
+		{"Scoped group", "/v1/transfer", http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, nil)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+		})
+	}
+}
+
+func TestWithReplayProtectionNilStore(t *testing.T) {
+	_, err := NewServer(WithReplayProtection(nil, ""))
+	assert.Error(t, err)
+}
@@ -11,20 +11,96 @@ import (
 
 // ServerRepo ...
 type ServerRepo interface {
+	// Middlewares returns the names of the installed built-in middlewares in
+	// the order they run
+	Middlewares() []string
+	// MiddlewaresHandler returns a handler exposing Middlewares as JSON
+	MiddlewaresHandler() HandlerFunc
+	MiddlewareLogger() MiddlewareFunc
+	MiddlewareRecover() MiddlewareFunc
+	MiddlewareCors() MiddlewareFunc
+	Use(middleware MiddlewareFunc)
+	Uses(middlewares ...MiddlewareFunc)
 	// NewContext creates a new Echo context
 	NewContext(req *http.Request, w http.ResponseWriter) Context
-	// RegisterRouters registers multiple routers with the specified group and middlewares
+	// RegisterRouters registers multiple routers with the specified group and middlewares.
+	// Every call for a group shares one echo group; the middlewares only wrap
+	// the routers of this call.
 	RegisterRouters(group Kind, routers *RegisterRouters, middlewares ...MiddlewareFunc) error
-	// Start starts the server
+	// Group returns the echo group RegisterRouters uses for kind, creating it
+	// if needed, so nested groups such as /v1/admin can be built on it with
+	// their own middlewares. ROOT returns a group without prefix on the echo
+	// instance carrying the ROOT scoped middlewares. It returns nil for an
+	// invalid kind.
+	Group(kind Kind) *echo.Group
+	// RegisterRoutersWithPrefix registers multiple routers under a custom path
+	// prefix such as "/internal", for groups the Kind enum doesn't cover. Routes
+	// are listed with the ROOT group and only global auth applies to them.
+	RegisterRoutersWithPrefix(prefix string, routers *RegisterRouters, middlewares ...MiddlewareFunc) error
+	// RegisterGone registers a retired endpoint answering 410 Gone with the given message for every method
+	RegisterGone(group Kind, path, message string) error
+	// Start starts the server in the background and exits the process if it
+	// cannot bind or serve; use StartListening to handle those errors
 	Start()
-	// GetEcho returns the Echo instance
+	// StartListening binds the listener and serves until the server is shut
+	// down, returning bind and serve errors to the caller instead of exiting.
+	// It returns nil after a graceful shutdown.
+	StartListening() error
+	// Addr returns the address the server listens on. Before Start, and once
+	// the server is shut down, there is no listener and it returns an empty
+	// string instead of blocking; wait on Listening to get the bound address.
+	Addr() string
+	// Started reports whether the server is listening. Requests can still be
+	// served through the echo instance before Start, e.g. in tests, but
+	// readiness and admin endpoints answer 503 until it returns true.
+	Started() bool
+	// Listening returns a channel closed once the listener is bound and the
+	// server accepts connections
+	Listening() <-chan struct{}
+	// RunWithSignals starts the server and blocks until a shutdown signal is
+	// received or ctx is cancelled, then shuts the server down gracefully. The
+	// signals are SIGINT and SIGTERM unless set with WithShutdownSignals. It
+	// returns bind, serve and shutdown errors.
+	RunWithSignals(ctx context.Context) error
+	// OnStart registers a hook run once the server starts; the server is not
+	// ready until every hook has returned without error
+	OnStart(fn func(ctx context.Context) error)
+	// RegisterOnShutdown registers a cleanup function, e.g. closing a database
+	// pool, run by Shutdown once in-flight requests have drained. Functions run
+	// in reverse registration order and all of them run even if one fails.
+	RegisterOnShutdown(fn func(ctx context.Context) error)
+	// MarkReady flags the server as ready to receive traffic
+	MarkReady()
+	// IsReady reports whether the server is running and not shutting down, the
+	// start hooks completed and MarkReady was called
+	IsReady() bool
+	// ReadinessHandler returns a handler answering 200 when the server is ready
+	// and 503 otherwise
+	ReadinessHandler() HandlerFunc
+	// GetEcho returns the Echo instance serving requests, which changes when
+	// ResetRouters runs
 	GetEcho() *echo.Echo
-	// GetRouters returns all registered routes
+	// GetRouters returns all registered routes sorted by path then method
 	GetRouters() []*Route
-	// Close closes the server
+	// Close stops the server immediately, dropping in-flight requests. Closing
+	// a server that never started is a no-op and it can still be started, and
+	// closing it again is a no-op. It may be called while Shutdown is draining
+	// to cut it short.
 	Close() error
-	// Shutdown gracefully shuts down the server
+	// Shutdown gracefully shuts down the server. When ctx has a deadline, the
+	// contexts of in-flight requests are cancelled a grace period before it so
+	// handlers can bail out instead of being cut off. The grace is capped at
+	// half the time left, so a short deadline doesn't cancel them right away.
+	// The RegisterOnShutdown functions run once the server has drained and
+	// their errors are returned along with the shutdown error. Open WebSocket
+	// connections are closed with a going away status. Shutting down a server
+	// that is not running is a no-op, and one that never started can still be
+	// started afterwards.
 	Shutdown(ctx context.Context) error
-	// GracefulShutdown shuts down the server with a timeout
+	// GracefulShutdown shuts down the server within the WithShutdownTimeout
+	// timeout, 3 seconds by default. With WithPreShutdownDelay, readiness
+	// answers 503 first and requests keep being served for the delay, giving
+	// load balancers time to deregister the instance before it drains. The
+	// delay comes on top of the timeout and calling Close cuts it short.
 	GracefulShutdown() error
 }
@@ -0,0 +1,73 @@
+package server
+
+import (
+	"crypto/rand"
+	"fmt"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	requestIDKey = "echowr.request_id"
+
+	// maxRequestIDLength bounds the client supplied IDs that are trusted
+	maxRequestIDLength = 128
+)
+
+// RequestID returns the ID of the request as read from X-Request-Id or
+// generated by WithRequestID, or an empty string when the option is not set
+func RequestID(c Context) string {
+	id, _ := c.Get(requestIDKey).(string)
+	return id
+}
+
+// logRequestID returns the request ID for log records: the one set by
+// WithRequestID, or else the X-Request-Id sent by the client
+func logRequestID(c Context) string {
+	if id := RequestID(c); len(id) > 0 {
+		return id
+	}
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
+
+// requestID reuses the X-Request-Id sent by the client, or generates a UUID
+// when it is missing or malformed, stores it in the context and echoes it in
+// the response so callers can correlate logs across services
+func requestID() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			id := c.Request().Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(id) {
+				id = newUUID()
+			}
+
+			c.Set(requestIDKey, id)
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			return next(c)
+		}
+	}
+}
+
+// validRequestID accepts non-empty printable ASCII IDs of bounded length,
+// keeping control characters out of the logs
+func validRequestID(id string) bool {
+	if len(id) == 0 || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
+
+// newUUID returns a random UUID version 4
+func newUUID() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
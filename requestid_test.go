@@ -0,0 +1,82 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestID(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger), WithAccessLog(), WithRequestID())
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, RequestID(c))
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name   string
+		header string
+		keep   bool
+	}{
+		{"propagated", "abc-123", true},
+		{"generated", "", false},
+		{"malformed", "bad id\n", false},
+		{"too long", strings.Repeat("x", maxRequestIDLength+1), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			buf.Reset()
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if len(tt.header) > 0 {
+				req.Header.Set(echo.HeaderXRequestID, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			id := rec.Header().Get(echo.HeaderXRequestID)
+			assert.Equal(t, id, rec.Body.String())
+			if tt.keep {
+				assert.Equal(t, tt.header, id)
+			} else {
+				assert.True(t, IsUUIDv4(id), id)
+			}
+
+			assert.NoError(t, logger.Flush())
+			records := accessLogRecords(t, &buf)
+			if assert.Len(t, records, 1) {
+				assert.Equal(t, id, records[0]["request_id"])
+			}
+		})
+	}
+}
+
+func TestRequestIDDisabled(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, RequestID(c))
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/test", nil))
+
+	assert.Empty(t, rec.Body.String())
+	assert.Empty(t, rec.Header().Get(echo.HeaderXRequestID))
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ResourceHandlers are the CRUD handlers of a resource; nil handlers are
+// not registered
+type ResourceHandlers struct {
+	List   HandlerFunc
+	Get    HandlerFunc
+	Create HandlerFunc
+	Update HandlerFunc
+	Delete HandlerFunc
+}
+
+// RegisterResource registers the CRUD routes of a resource on group:
+// GET base, POST base, GET base/:id, PUT base/:id and DELETE base/:id, each
+// only when its handler is set
+func (s *Server) RegisterResource(group Kind, base string, h ResourceHandlers, middlewares ...MiddlewareFunc) error {
+	if !strings.HasPrefix(base, "/") {
+		return fmt.Errorf("resource path must start with /: %q", base)
+	}
+	base = strings.TrimSuffix(base, "/")
+
+	collection := Methods{}
+	if h.List != nil {
+		collection[http.MethodGet] = h.List
+	}
+	if h.Create != nil {
+		collection[http.MethodPost] = h.Create
+	}
+
+	item := Methods{}
+	if h.Get != nil {
+		item[http.MethodGet] = h.Get
+	}
+	if h.Update != nil {
+		item[http.MethodPut] = h.Update
+	}
+	if h.Delete != nil {
+		item[http.MethodDelete] = h.Delete
+	}
+
+	if len(collection) == 0 && len(item) == 0 {
+		return fmt.Errorf("resource %s has no handlers", base)
+	}
+
+	rr := NewRouters()
+	if len(collection) > 0 {
+		if err := rr.AddRouter(base, collection); err != nil {
+			return err
+		}
+	}
+	if len(item) > 0 {
+		if err := rr.AddRouter(base+"/:id", item); err != nil {
+			return err
+		}
+	}
+
+	return s.RegisterRouters(group, rr, middlewares...)
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRegisterResource(t *testing.T) {
+	server, _ := NewServer()
+
+	err := server.RegisterResource(V1, "/books", ResourceHandlers{
+		List: func(c Context) error {
+			return c.String(http.StatusOK, "all books")
+		},
+		Get: func(c Context) error {
+			return c.String(http.StatusOK, "book "+c.Param("id"))
+		},
+	})
+	assert.NoError(t, err)
+
+	assert.Equal(t, []RouteEntry{
+		{Group: V1, Method: http.MethodGet, Path: "/v1/books"},
+		{Group: V1, Method: http.MethodGet, Path: "/v1/books/:id"},
+	}, server.ListRoutes())
+
+	tests := []struct {
+		method string
+		path   string
+		status int
+		body   string
+	}{
+		{http.MethodGet, "/v1/books", http.StatusOK, "all books"},
+		{http.MethodGet, "/v1/books/7", http.StatusOK, "book 7"},
+		{http.MethodPost, "/v1/books", http.StatusMethodNotAllowed, ""},
+		{http.MethodPut, "/v1/books/7", http.StatusMethodNotAllowed, ""},
+		{http.MethodDelete, "/v1/books/7", http.StatusMethodNotAllowed, ""},
+	}
+
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(tt.method, tt.path, nil))
+
+		assert.Equal(t, tt.status, rec.Code, tt.method+" "+tt.path)
+		if len(tt.body) > 0 {
+			assert.Equal(t, tt.body, rec.Body.String())
+		}
+	}
+}
+
+func TestRegisterResourceInvalid(t *testing.T) {
+	server, _ := NewServer()
+	list := func(c Context) error { return nil }
+
+	assert.Error(t, server.RegisterResource(V1, "/books", ResourceHandlers{}))
+	assert.Error(t, server.RegisterResource(V1, "books", ResourceHandlers{List: list}))
+}
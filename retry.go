@@ -0,0 +1,45 @@
+package server
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// HeaderRetryCount carries the client's retry attempt number, 0 being
+	// the first attempt
+	HeaderRetryCount = "X-Retry-Count"
+
+	retryCountKey = "echowr.retry_count"
+)
+
+// RetryCount returns the retry attempt of the request as sent in the
+// X-Retry-Count header. It is 0 for first attempts, malformed headers and
+// servers without WithRetryCount.
+func RetryCount(c Context) int {
+	n, _ := c.Get(retryCountKey).(int)
+	return n
+}
+
+// retryCount stores the request retry attempt in the context and, when
+// limit is positive, rejects attempts beyond it with 429 so clients stuck in
+// a retry loop back off
+func retryCount(limit int) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			n, err := strconv.Atoi(c.Request().Header.Get(HeaderRetryCount))
+			if err != nil || n < 0 {
+				return next(c)
+			}
+			c.Set(retryCountKey, n)
+
+			if limit > 0 && n > limit {
+				return echo.NewHTTPError(http.StatusTooManyRequests, "too many retries")
+			}
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryCount(t *testing.T) {
+	tests := []struct {
+		name         string
+		limit        int
+		header       string
+		expectedCode int
+		expectedBody string
+	}{
+		{"No header", 0, "", http.StatusOK, "0"},
+		{"Retry attempt", 0, "3", http.StatusOK, "3"},
+		{"Malformed header", 0, "three", http.StatusOK, "0"},
+		{"Negative header", 0, "-1", http.StatusOK, "0"},
+		{"Within limit", 3, "3", http.StatusOK, "3"},
+		{"Beyond limit", 3, "4", http.StatusTooManyRequests, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(WithRetryCount(tt.limit))
+			assert.NoError(t, err)
+
+			rr := NewRouters()
+			rr.AddRouter("/retry", Methods{
+				http.MethodGet: func(c Context) error {
+					return c.String(http.StatusOK, strconv.Itoa(RetryCount(c)))
+				},
+			})
+			assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+			req := httptest.NewRequest(http.MethodGet, "/retry", nil)
+			if len(tt.header) > 0 {
+				req.Header.Set(HeaderRetryCount, tt.header)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithRetryCountInvalid(t *testing.T) {
+	_, err := NewServer(WithRetryCount(-1))
+	assert.Error(t, err)
+}
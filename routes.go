@@ -0,0 +1,218 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// RouteEntry describes a single method of a route registered through the
+// server
+type RouteEntry struct {
+	Group   Kind
+	Method  string
+	Path    string
+	Summary string
+}
+
+// RouteInfo describes a path served by echo along with the group it was
+// registered under and its methods. Name is the handler name when every
+// method shares it, empty otherwise.
+type RouteInfo struct {
+	Group   Kind
+	Path    string
+	Methods []string
+	Name    string
+}
+
+// registeredRoute is a registry entry along with the echo handler name,
+// used to spot routes overridden behind the server's back
+type registeredRoute struct {
+	RouteEntry
+	name   string
+	hidden bool
+}
+
+// RouteDiscrepancy is a difference between the server's route registry and
+// the routes known to echo
+type RouteDiscrepancy struct {
+	Method string
+	Path   string
+	Reason string
+}
+
+// Validate reports the registration mistakes found so far, such as methods
+// mapped to a nil handler, as a single joined error. Start runs it before
+// binding and refuses to serve an invalid route table.
+func (s *Server) Validate() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return errors.Join(s.invalid...)
+}
+
+// ListRoutes returns the routes registered through RegisterRouters sorted
+// by path then method. Hidden routes and routes added directly on the echo
+// instance are not included.
+func (s *Server) ListRoutes() []RouteEntry {
+	s.mu.RLock()
+	routes := make([]RouteEntry, 0, len(s.registry))
+	for _, r := range s.registry {
+		if r.hidden {
+			continue
+		}
+		routes = append(routes, r.RouteEntry)
+	}
+	s.mu.RUnlock()
+
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+
+	return routes
+}
+
+// RouteTable returns the routes known to echo with one row per group and
+// path, sorted by path with the methods of each row sorted. The group comes from the registry, or is
+// inferred from the path for routes added directly on the echo instance.
+// Hidden routes are not included.
+func (s *Server) RouteTable() []RouteInfo {
+	s.mu.RLock()
+	known := make(map[string]registeredRoute, len(s.registry))
+	for _, r := range s.registry {
+		known[r.Method+" "+r.Path] = r
+	}
+	s.mu.RUnlock()
+
+	var table []RouteInfo
+	index := map[string]int{}
+	for _, route := range s.live.Load().Routes() {
+		if route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		group := routeGroup(route.Path)
+		if r, ok := known[route.Method+" "+route.Path]; ok {
+			if r.hidden {
+				continue
+			}
+			group = r.Group
+		}
+
+		key := group.String() + " " + route.Path
+		i, ok := index[key]
+		if !ok {
+			i = len(table)
+			index[key] = i
+			table = append(table, RouteInfo{Group: group, Path: route.Path, Name: route.Name})
+		} else if table[i].Name != route.Name {
+			table[i].Name = ""
+		}
+		table[i].Methods = append(table[i].Methods, route.Method)
+	}
+
+	for _, info := range table {
+		sort.Strings(info.Methods)
+	}
+	sort.Slice(table, func(i, j int) bool {
+		if table[i].Path != table[j].Path {
+			return table[i].Path < table[j].Path
+		}
+		return table[i].Group < table[j].Group
+	})
+
+	return table
+}
+
+// SyncRoutes reconciles the route registry with echo.Routes(), logging a
+// warning for every route added directly on GetEcho() ("untracked") and for
+// every registered route whose handler was replaced that way ("overridden").
+// Untracked routes are adopted into the registry so ListRoutes reflects what
+// is served.
+func (s *Server) SyncRoutes() []RouteDiscrepancy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	known := make(map[string]int, len(s.registry))
+	for i, r := range s.registry {
+		known[r.Method+" "+r.Path] = i
+	}
+
+	var discrepancies []RouteDiscrepancy
+	for _, route := range s.live.Load().Routes() {
+		if route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		reason := ""
+		i, ok := known[route.Method+" "+route.Path]
+		switch {
+		case !ok:
+			reason = "untracked"
+			s.registry = append(s.registry, registeredRoute{
+				RouteEntry: RouteEntry{
+					Group:  routeGroup(route.Path),
+					Method: route.Method,
+					Path:   route.Path,
+				},
+				name: route.Name,
+			})
+		case s.registry[i].name != route.Name:
+			reason = "overridden"
+			s.registry[i].name = route.Name
+		default:
+			continue
+		}
+
+		discrepancies = append(discrepancies, RouteDiscrepancy{
+			Method: route.Method,
+			Path:   route.Path,
+			Reason: reason,
+		})
+		s.log(slog.WarnLevel, "route registry out of sync", slog.M{
+			"method": route.Method,
+			"path":   route.Path,
+			"reason": reason,
+		})
+	}
+
+	return discrepancies
+}
+
+// routeGroup infers the group of a path from its first segment
+func routeGroup(path string) Kind {
+	segment, _, _ := strings.Cut(strings.TrimPrefix(path, "/"), "/")
+	for k := V1; k <= DOCS; k++ {
+		if segment == k.String() {
+			return k
+		}
+	}
+	return ROOT
+}
+
+// GenerateRoutesMarkdown renders ListRoutes as a Markdown table, suitable
+// for committing to docs or serving as text/markdown
+func (s *Server) GenerateRoutesMarkdown() string {
+	var b strings.Builder
+	b.WriteString("| Group | Method | Path | Summary |\n")
+	b.WriteString("|-------|--------|------|---------|\n")
+
+	for _, r := range s.ListRoutes() {
+		fmt.Fprintf(&b, "| %s | %s | `%s` | %s |\n",
+			r.Group, r.Method, r.Path, markdownCell(r.Summary))
+	}
+
+	return b.String()
+}
+
+// markdownCell escapes text for use inside a Markdown table cell
+func markdownCell(text string) string {
+	text = strings.ReplaceAll(text, "|", `\|`)
+	return strings.Join(strings.Fields(text), " ")
+}
@@ -0,0 +1,216 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListRoutes(t *testing.T) {
+	server, _ := NewServer()
+	handler := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	rr := NewRouters()
+	rr.AddRoute(RegisterRouter{
+		Path:    "/users",
+		Methods: Methods{http.MethodPost: handler, http.MethodGet: handler},
+		Summary: "Manage users",
+	})
+	rr.AddRouter("/health", Methods{http.MethodGet: handler})
+
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	assert.Equal(t, []RouteEntry{
+		{Group: V1, Method: http.MethodGet, Path: "/v1/health"},
+		{Group: V1, Method: http.MethodGet, Path: "/v1/users", Summary: "Manage users"},
+		{Group: V1, Method: http.MethodPost, Path: "/v1/users", Summary: "Manage users"},
+	}, server.ListRoutes())
+}
+
+func TestGenerateRoutesMarkdown(t *testing.T) {
+	server, _ := NewServer()
+	handler := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	rr := NewRouters()
+	rr.AddRoute(RegisterRouter{
+		Path:    "/users/:id",
+		Methods: Methods{http.MethodGet: handler},
+		Summary: "Fetch a user | by id",
+	})
+
+	assert.NoError(t, server.RegisterRouters(API, rr))
+
+	md := server.GenerateRoutesMarkdown()
+	lines := strings.Split(strings.TrimSpace(md), "\n")
+
+	assert.Len(t, lines, 3)
+	assert.Equal(t, "| Group | Method | Path | Summary |", lines[0])
+	assert.Equal(t, "| api | GET | `/api/users/:id` | Fetch a user \\| by id |", lines[2])
+}
+
+func TestSyncRoutes(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	server, _ := NewServer(WithSlog(logger))
+	handler := func(c Context) error { return c.String(http.StatusOK, "registered") }
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{http.MethodGet: handler})
+	rr.AddRouter("/orders", Methods{http.MethodGet: handler})
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	e := server.GetEcho()
+	e.GET("/metrics", func(c Context) error { return c.String(http.StatusOK, "direct") })
+	e.GET("/v1/orders", func(c Context) error { return c.String(http.StatusOK, "direct") })
+
+	discrepancies := server.SyncRoutes()
+	sort.Slice(discrepancies, func(i, j int) bool {
+		return discrepancies[i].Path < discrepancies[j].Path
+	})
+
+	assert.Equal(t, []RouteDiscrepancy{
+		{Method: http.MethodGet, Path: "/metrics", Reason: "untracked"},
+		{Method: http.MethodGet, Path: "/v1/orders", Reason: "overridden"},
+	}, discrepancies)
+
+	assert.NoError(t, logger.Flush())
+	assert.Equal(t, 2, strings.Count(buf.String(), "route registry out of sync"))
+
+	assert.Contains(t, server.ListRoutes(), RouteEntry{Group: ROOT, Method: http.MethodGet, Path: "/metrics"})
+	assert.Empty(t, server.SyncRoutes())
+}
+
+func TestRouteGroup(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected Kind
+	}{
+		{"/", ROOT},
+		{"/metrics", ROOT},
+		{"/v1", V1},
+		{"/v2/users/:id", V2},
+		{"/docs/index", DOCS},
+		{"/v10/users", ROOT},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.path, func(t *testing.T) {
+			assert.Equal(t, tt.expected, routeGroup(tt.path))
+		})
+	}
+}
+
+func TestValidate(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	ok := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{http.MethodGet: ok, http.MethodPost: nil})
+	rr.AddRouter("/orders", Methods{http.MethodDelete: nil})
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	err := server.Validate()
+	if assert.Error(t, err) {
+		assert.Contains(t, err.Error(), "nil handler for POST /users")
+		assert.Contains(t, err.Error(), "nil handler for DELETE /orders")
+	}
+
+	// the valid method is still served, the nil ones are not registered
+	assert.Equal(t, []RouteEntry{{Group: V1, Method: http.MethodGet, Path: "/v1/users"}}, server.ListRoutes())
+
+	assert.Equal(t, err, server.StartListening())
+	assert.Empty(t, server.Addr())
+}
+
+func TestValidateNoErrors(t *testing.T) {
+	server, _ := NewServer()
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{http.MethodGet: func(c Context) error { return nil }})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	assert.NoError(t, server.Validate())
+}
+
+func TestListRoutesHidden(t *testing.T) {
+	server, _ := NewServer()
+	handler := func(c Context) error { return c.String(http.StatusOK, c.Path()) }
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{http.MethodGet: handler})
+	rr.AddRoute(RegisterRouter{
+		Path:    "/debug",
+		Methods: Methods{http.MethodGet: handler},
+		Hidden:  true,
+	})
+	rr.AddRoute(RegisterRouter{
+		Path:    "/pprof",
+		Methods: Methods{http.MethodGet: handler},
+		DevOnly: true,
+	})
+
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+	assert.NoError(t, server.RegisterRouters(DEV, rr))
+
+	assert.Equal(t, []RouteEntry{
+		{Group: DEV, Method: http.MethodGet, Path: "/dev/pprof"},
+		{Group: DEV, Method: http.MethodGet, Path: "/dev/users"},
+		{Group: V1, Method: http.MethodGet, Path: "/v1/users"},
+	}, server.ListRoutes())
+	assert.NotContains(t, server.GenerateRoutesMarkdown(), "/debug")
+	assert.Empty(t, server.SyncRoutes())
+
+	for path, code := range map[string]int{
+		"/v1/debug":  http.StatusOK,
+		"/dev/debug": http.StatusOK,
+		"/dev/pprof": http.StatusOK,
+		"/v1/pprof":  http.StatusNotFound,
+	} {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, rec.Code, path)
+	}
+}
+
+func TestRouteTable(t *testing.T) {
+	server, _ := NewServer()
+	handler := func(c Context) error { return c.NoContent(http.StatusOK) }
+	other := func(c Context) error { return c.NoContent(http.StatusAccepted) }
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodPost: handler,
+		http.MethodGet:  handler,
+		http.MethodPut:  other,
+	})
+	rr.AddRouter("/orders", Methods{
+		http.MethodPost: handler,
+		http.MethodGet:  handler,
+	})
+	rr.AddRoute(RegisterRouter{
+		Path:    "/debug",
+		Methods: Methods{http.MethodGet: handler},
+		Hidden:  true,
+	})
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+	server.GetEcho().GET("/api/ping", other)
+
+	names := map[string]string{}
+	for _, route := range server.GetRouters() {
+		names[route.Method+" "+route.Path] = route.Name
+	}
+
+	assert.Equal(t, []RouteInfo{
+		{Group: API, Path: "/api/ping", Methods: []string{http.MethodGet}, Name: names["GET /api/ping"]},
+		{Group: V1, Path: "/v1/orders", Methods: []string{http.MethodGet, http.MethodPost}, Name: names["GET /v1/orders"]},
+		{Group: V1, Path: "/v1/users", Methods: []string{http.MethodGet, http.MethodPost, http.MethodPut}},
+	}, server.RouteTable())
+}
@@ -5,15 +5,47 @@ package server
 
 import (
 	"context"
+	"crypto/tls"
+	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"os/signal"
+	"sort"
 	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
 	"time"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
+	"golang.org/x/net/netutil"
 )
 
+var (
+	// ErrServerStarted is returned when starting a server twice
+	ErrServerStarted = errors.New("server already started")
+	// ErrServerStopped is returned when starting a server that was shut down
+	ErrServerStopped = errors.New("server stopped")
+)
+
+// lifecycle states of a server
+const (
+	stateNew int = iota
+	stateRunning
+	stateStopped
+)
+
+// defaultShutdownTimeout bounds GracefulShutdown when WithShutdownTimeout
+// is not set
+const defaultShutdownTimeout = 3 * time.Second
+
+// defaultShutdownGrace is how long before the shutdown deadline in-flight
+// requests are cancelled when WithShutdownGrace is not set
+const defaultShutdownGrace = 500 * time.Millisecond
+
 // Kind represents the type of router group
 type Kind int
 
@@ -43,12 +75,41 @@ func (k Kind) String() string {
 type RegisterRouter struct {
 	Path    string
 	Methods map[string]HandlerFunc
+
+	// NoCompression excludes the router from response compression
+	NoCompression bool
+
+	// Summary is a one-line description used in route documentation
+	Summary string
+
+	// Middlewares run only for this router's methods, after the group ones
+	Middlewares []MiddlewareFunc
+
+	// MethodMiddlewares run only for the keyed method, after Middlewares
+	MethodMiddlewares map[string][]MiddlewareFunc
+
+	// WebSocket serves GET requests as WebSocket connections, see AddWebSocket
+	WebSocket WebSocketHandler
+
+	// Hidden serves the router but leaves it out of ListRoutes and the
+	// generated route documentation
+	Hidden bool
+
+	// DevOnly registers the router only when it is added to the DEV group
+	DevOnly bool
+
+	// builtin marks the health, metrics and admin endpoints the server
+	// registers itself
+	builtin bool
 }
 
 // RegisterRouters holds multiple routers with a fixed path prefix
 type RegisterRouters struct {
 	PathFixed string
 	Routers   []RegisterRouter
+
+	index   map[string][]int // router positions by path
+	indexed int              // number of routers covered by index
 }
 
 // NewRouters creates a new instance of RegisterRouters
@@ -56,16 +117,54 @@ func NewRouters() *RegisterRouters {
 	return &RegisterRouters{}
 }
 
-// AddRouter adds a new router to the list
-func (r *RegisterRouters) AddRouter(path string, methods map[string]HandlerFunc) {
-	r.Routers = append(r.Routers, RegisterRouter{
+// AddRouter adds a new router to the list, failing if one of its methods is
+// already registered for the path
+func (r *RegisterRouters) AddRouter(path string, methods map[string]HandlerFunc) error {
+	return r.add(RegisterRouter{
 		Path:    path,
 		Methods: methods,
 	})
 }
 
-// AddRouterFx adds a new router with a fixed path prefix
-func (r *RegisterRouters) AddRouterFx(params string, methods map[string]HandlerFunc) {
+// AddRouterWithMiddleware adds a new router whose middlewares only apply to
+// its own methods, failing if one of its methods is already registered for
+// the path
+func (r *RegisterRouters) AddRouterWithMiddleware(path string, methods map[string]HandlerFunc, middlewares ...MiddlewareFunc) error {
+	return r.add(RegisterRouter{
+		Path:        path,
+		Methods:     methods,
+		Middlewares: middlewares,
+	})
+}
+
+// AddRouterWithMethodMiddleware adds a new router where each method carries
+// its own middlewares, failing if one of its methods is already registered
+// for the path
+func (r *RegisterRouters) AddRouterWithMethodMiddleware(path string, methods MethodsWithMiddleware) error {
+	router := RegisterRouter{
+		Path:              path,
+		Methods:           make(Methods, len(methods)),
+		MethodMiddlewares: make(map[string][]MiddlewareFunc, len(methods)),
+	}
+	for method, m := range methods {
+		router.Methods[method] = m.Handler
+		if len(m.Middlewares) > 0 {
+			router.MethodMiddlewares[method] = m.Middlewares
+		}
+	}
+
+	return r.add(router)
+}
+
+// AddRoute adds a fully configured router to the list, failing if one of
+// its methods is already registered for the path
+func (r *RegisterRouters) AddRoute(router RegisterRouter) error {
+	return r.add(router)
+}
+
+// AddRouterFx adds a new router with a fixed path prefix, failing if one of
+// its methods is already registered for the path
+func (r *RegisterRouters) AddRouterFx(params string, methods map[string]HandlerFunc) error {
 	path := strings.TrimSpace(params)
 	if len(path) > 0 {
 		path = r.PathFixed + path
@@ -73,12 +172,44 @@ func (r *RegisterRouters) AddRouterFx(params string, methods map[string]HandlerF
 		path = r.PathFixed
 	}
 
-	r.Routers = append(r.Routers, RegisterRouter{
+	return r.add(RegisterRouter{
 		Path:    path,
 		Methods: methods,
 	})
 }
 
+// add appends a router after checking its path and methods are new
+func (r *RegisterRouters) add(router RegisterRouter) error {
+	r.reindex()
+
+	for _, i := range r.index[router.Path] {
+		for method := range router.Methods {
+			if _, ok := r.Routers[i].Methods[method]; ok {
+				return fmt.Errorf("duplicate route: %s %s", method, router.Path)
+			}
+		}
+	}
+
+	r.index[router.Path] = append(r.index[router.Path], len(r.Routers))
+	r.Routers = append(r.Routers, router)
+	r.indexed++
+
+	return nil
+}
+
+// reindex rebuilds the path index when Routers was changed directly
+func (r *RegisterRouters) reindex() {
+	if r.index != nil && r.indexed == len(r.Routers) {
+		return
+	}
+
+	r.index = make(map[string][]int, len(r.Routers))
+	for i, router := range r.Routers {
+		r.index[router.Path] = append(r.index[router.Path], i)
+	}
+	r.indexed = len(r.Routers)
+}
+
 // GetAllRouters returns all registered routers
 func (r *RegisterRouters) GetAllRouters() []RegisterRouter {
 	return r.Routers
@@ -86,11 +217,11 @@ func (r *RegisterRouters) GetAllRouters() []RegisterRouter {
 
 // GetRouters returns routers matching the specified path
 func (r *RegisterRouters) GetRouters(path string) []RegisterRouter {
+	r.reindex()
+
 	var routers []RegisterRouter
-	for _, router := range r.Routers {
-		if router.Path == path {
-			routers = append(routers, router)
-		}
+	for _, i := range r.index[path] {
+		routers = append(routers, r.Routers[i])
 	}
 	return routers
 }
@@ -112,17 +243,69 @@ func (r *RegisterRouters) SetPathFixed(path string) {
 }
 
 type Methods map[string]HandlerFunc
+
+// MethodHandler is a handler with middlewares applied to its method only
+type MethodHandler struct {
+	Handler     HandlerFunc
+	Middlewares []MiddlewareFunc
+}
+
+// MethodsWithMiddleware maps methods to handlers with their own middlewares
+type MethodsWithMiddleware map[string]MethodHandler
+
 type HandlerFunc = echo.HandlerFunc
 type MiddlewareFunc = echo.MiddlewareFunc
 type Context = echo.Context
 type Route = echo.Route
 
+// JSONSerializer encodes and decodes the JSON handled by c.JSON and c.Bind,
+// see WithJSONSerializer. Serialize writes i to c.Response(), indented when
+// indent is not empty. Deserialize decodes c.Request().Body into i and
+// should return a 400 HTTPError for malformed input. A custom serializer
+// replaces the built-in one along with its descriptive bind errors, so it
+// can't be combined with WithJSONUseNumber.
+type JSONSerializer = echo.JSONSerializer
+
 // Server represents the HTTP server
 type Server struct {
-	port   string
-	host   string
-	echo   *echo.Echo
-	params *ServerParams
+	port       string
+	host       string
+	echo       *echo.Echo                // registration target, see ResetRouters
+	front      *echo.Echo                // owns the listeners
+	live       atomic.Pointer[echo.Echo] // serves requests
+	params     *ServerParams
+	draining   atomic.Bool
+	recent     *requestRing
+	duplicates *duplicateDetector
+	hedges     *hedger
+	incomplete atomic.Uint64
+	routes     int
+	metrics    *metrics
+	sockets    webSockets
+
+	mu            sync.RWMutex
+	registry      []registeredRoute
+	groups        map[Kind]*echo.Group
+	rootScoped    []MiddlewareFunc
+	rootGroup     *echo.Group
+	invalid       []error
+	noCompression map[string]bool
+	builtin       map[string]bool
+	middlewares   []string
+	replays       []func() error
+	startHooks    []func(ctx context.Context) error
+	shutdownHooks []func(ctx context.Context) error
+	hooksDone     atomic.Bool
+	ready         atomic.Bool
+
+	baseCtx    context.Context
+	cancelBase context.CancelFunc
+	listening  chan struct{}
+	listenOnce sync.Once
+
+	lifecycle sync.Mutex
+	state     int
+	closed    bool
 }
 
 // NewServer creates a new server instance with the given options
@@ -132,16 +315,278 @@ func NewServer(opts ...Options) (*Server, error) {
 		return nil, err
 	}
 
+	s := &Server{
+		port:          params.GetPort(),
+		host:          params.GetHost(),
+		params:        params,
+		noCompression: make(map[string]bool),
+		builtin:       make(map[string]bool),
+		groups:        make(map[Kind]*echo.Group),
+		listening:     make(chan struct{}),
+	}
+
+	s.baseCtx, s.cancelBase = context.WithCancel(context.Background())
+
+	s.echo = s.newEcho()
+	s.front = s.echo
+	s.front.Pre(s.forwardReloaded())
+	s.live.Store(s.echo)
+
+	if err := s.install(); err != nil {
+		return nil, err
+	}
+
+	if prefix := params.GetAdminPrefix(); len(prefix) > 0 {
+		if err := s.registerAdmin(prefix, params.GetAdminAuth()); err != nil {
+			return nil, err
+		}
+	}
+
+	return s, nil
+}
+
+// newEcho creates an echo instance configured from the options
+func (s *Server) newEcho() *echo.Echo {
+	params := s.params
 	e := echo.New()
 
 	e.HideBanner = true
+	e.JSONSerializer = jsonSerializer{useNumber: params.GetJSONUseNumber()}
+	if serializer := params.GetJSONSerializer(); serializer != nil {
+		e.JSONSerializer = serializer
+	}
+	e.Validator = params.GetValidator()
+	if params.GetStrictQueryParams() {
+		e.Binder = &strictQueryBinder{}
+	}
+	if proxies := params.GetTrustedProxies(); len(proxies) > 0 {
+		e.IPExtractor = trustedProxiesExtractor(proxies)
+	}
+	e.TLSServer.TLSConfig = params.tlsConfig()
+
+	for _, hs := range []*http.Server{e.Server, e.TLSServer} {
+		hs.ReadTimeout = params.GetReadTimeout()
+		hs.ReadHeaderTimeout = params.GetReadHeaderTimeout()
+		hs.WriteTimeout = params.GetWriteTimeout()
+		hs.IdleTimeout = params.GetIdleTimeout()
+	}
 
-	return &Server{
-		echo:   e,
-		port:   params.GetPort(),
-		host:   params.GetHost(),
-		params: params,
-	}, nil
+	baseContext := func(net.Listener) context.Context { return s.baseCtx }
+	e.Server.BaseContext = baseContext
+	e.TLSServer.BaseContext = baseContext
+
+	return e
+}
+
+// install adds the built-in middlewares enabled by the options to the echo
+// instance. Stateful ones such as metrics are created on the first install
+// and kept when ResetRouters installs them again.
+func (s *Server) install() error {
+	params := s.params
+
+	if host := params.GetCanonicalHost(); len(host) > 0 {
+		ch, err := parseCanonicalHost(host)
+		if err != nil {
+			return err
+		}
+		ch.proxies = params.GetCanonicalProxy()
+		s.pre("canonical-host", ch.middleware())
+	}
+
+	if gs := params.GetGRPCWeb(); gs != nil {
+		s.pre("grpc-web", grpcWeb(gs))
+	}
+
+	if params.GetRequestID() {
+		s.use("request-id", requestID())
+	}
+
+	if proto, minTLS := params.GetMinProtocol(), params.GetMinTLSVersion(); len(proto) > 0 || minTLS > 0 {
+		s.use("upgrade-required", requireProtocol(proto, minTLS))
+	}
+
+	s.use("close-on-drain", s.closeOnDrain())
+	s.use("unescape-params", unescapeParams())
+	s.use("upload-cleanup", s.cleanupUploads())
+
+	if limit := params.GetBodyLimit(); len(limit) > 0 {
+		s.use("body-limit", middleware.BodyLimit(limit))
+	}
+
+	if spec := params.GetMockSpec(); len(spec) > 0 {
+		mock, err := parseOpenAPIMock(spec)
+		if err != nil {
+			return err
+		}
+		s.use("openapi-mock", mock.middleware())
+	}
+
+	if params.GetBaggage() {
+		s.use("baggage", propagateBaggage())
+	}
+
+	if params.GetRetryCount() {
+		s.use("retry-count", retryCount(params.GetMaxRetryCount()))
+	}
+
+	if params.GetAccessLog() {
+		s.use("access-log", s.accessLog())
+	}
+
+	if h := params.GetLatencyHistogram(); h != nil {
+		s.use("latency-histogram", latencyHistogram(h))
+	}
+
+	if params.GetMetrics() {
+		if s.metrics == nil {
+			s.metrics = newMetrics(params.GetMetricsNamespace())
+		}
+		s.use("metrics", s.metrics.middleware())
+	}
+
+	if params.GetErrorLog() {
+		s.use("error-log", s.errorLog())
+	}
+
+	if params.GetGoroutineLeakDetection() {
+		s.use("goroutine-leak-detection", s.detectGoroutineLeaks(defaultGoroutineLeakThreshold))
+	}
+
+	if dir := params.GetRequestRecorder(); len(dir) > 0 {
+		s.use("request-recorder", s.recordToDisk(dir))
+	}
+
+	if params.useCORS() {
+		s.use("cors", params.cors())
+	}
+
+	if auth := globalScoped(params.GetAuth()); len(auth) > 0 {
+		s.use("auth", auth...)
+	}
+
+	if check := globalScoped(params.GetRequireCorrelationID()); len(check) > 0 {
+		s.use("correlation-id", check...)
+	}
+
+	if params.GetReplayStore() != nil && len(params.GetReplayGroups()) == 0 {
+		s.use("replay-protection", s.rejectReplays())
+	}
+
+	if params.GetIncompleteResponses() {
+		s.use("incomplete-responses", s.trackIncomplete())
+	}
+
+	if limit := params.GetBodyDrain(); limit > 0 {
+		s.use("body-drain", drainBody(limit))
+	}
+
+	if limit := params.GetBandwidthLimit(); limit > 0 {
+		s.use("bandwidth-limit", newBandwidthLimiter(limit, params.GetBandwidthKey()).middleware())
+	}
+
+	if params.GetCompression() {
+		s.use("compression", s.compression(params.GetCompressionLevel(), params.GetCompressionEncoding(),
+			params.GetCompressionMinLength(), params.GetCompressionSkipper()))
+	}
+
+	if fn := params.GetResponseTransform(); fn != nil {
+		s.use("response-transform", rewriteResponses(fn))
+	}
+
+	if dir := params.GetSPADir(); len(dir) > 0 {
+		s.use("spa", spa(dir, params.GetSPAAPIPrefixes()))
+	}
+
+	if size := params.GetBufferedWriter(); size > 0 {
+		s.use("buffered-writer", bufferedWriter(size))
+	}
+
+	if size := params.GetRecentRequests(); size > 0 {
+		if s.recent == nil {
+			s.recent = newRequestRing(size)
+		}
+		s.use("recent-requests", s.recordRequests())
+	}
+
+	if window := params.GetDuplicateWindow(); window > 0 {
+		if s.duplicates == nil {
+			s.duplicates = newDuplicateDetector(window, params.GetDuplicateKey())
+		}
+		s.use("duplicate-detection", s.detectDuplicates())
+	}
+
+	if params.GetHedging() {
+		if s.hedges == nil {
+			s.hedges = newHedger(params.GetHedgeHeader())
+			if s.metrics != nil {
+				s.hedges.cancelled = s.metrics.hedgeCancellations()
+			}
+		}
+		s.use("hedging", s.cancelHedged())
+	}
+
+	// recover goes last so a panic is turned into an error before it
+	// unwinds past the logging and metrics middlewares
+	if params.GetRecover() {
+		s.use("recover", s.recoverPanics())
+	}
+
+	return nil
+}
+
+// pre installs a named built-in middleware before routing
+func (s *Server) pre(name string, mws ...MiddlewareFunc) {
+	s.echo.Pre(mws...)
+	s.middlewares = append(s.middlewares, name)
+}
+
+// use installs a named built-in middleware after routing
+func (s *Server) use(name string, mws ...MiddlewareFunc) {
+	s.echo.Use(mws...)
+	s.middlewares = append(s.middlewares, name)
+}
+
+// Middlewares returns the names of the installed built-in middlewares in
+// the order they run
+func (s *Server) Middlewares() []string {
+	return append([]string(nil), s.middlewares...)
+}
+
+// MiddlewaresHandler returns a handler exposing Middlewares as JSON
+func (s *Server) MiddlewaresHandler() HandlerFunc {
+	return func(c Context) error {
+		return c.JSON(http.StatusOK, s.Middlewares())
+	}
+}
+
+// closeOnDrain marks responses with Connection: close once the server is
+// shutting down, so keep-alive clients don't reuse a dying connection
+func (s *Server) closeOnDrain() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			c.Response().Before(func() {
+				if s.draining.Load() {
+					c.Response().Header().Set(echo.HeaderConnection, "close")
+				}
+			})
+			return next(c)
+		}
+	}
+}
+
+// skipCompression skips compression for routers flagged with NoCompression
+func (s *Server) skipCompression(c Context) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.noCompression[c.Request().Method+" "+c.Path()]
+}
+
+// builtinRoute reports whether the request matched one of the health,
+// metrics or admin endpoints registered by the server
+func (s *Server) builtinRoute(c Context) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.builtin[c.Request().Method+" "+c.Path()]
 }
 
 func (s *Server) MiddlewareLogger() MiddlewareFunc {
@@ -157,154 +602,705 @@ func (s *Server) MiddlewareCors() MiddlewareFunc {
 }
 
 func (s *Server) Use(middleware MiddlewareFunc) {
-	s.echo.Use(middleware)
+	s.Uses(middleware)
 }
 
 func (s *Server) Uses(middlewares ...MiddlewareFunc) {
 	s.echo.Use(middlewares...)
+	s.recordReplay(func() error {
+		s.echo.Use(middlewares...)
+		return nil
+	})
 }
 
 // NewContext creates a new Echo context
 func (s *Server) NewContext(req *http.Request, w http.ResponseWriter) Context {
-	return s.echo.NewContext(req, w)
+	return s.live.Load().NewContext(req, w)
 }
 
-// RegisterRouters registers multiple routers with the specified group and middlewares
+// RegisterRouters registers multiple routers with the specified group and middlewares.
+// Every call for a group shares one echo group; the middlewares only wrap
+// the routers of this call.
 func (s *Server) RegisterRouters(group Kind, routers *RegisterRouters, middlewares ...MiddlewareFunc) error {
-	var grp any
+	register := func() error {
+		count, err := s.checkLimits(routers)
+		if err != nil {
+			return err
+		}
+
+		grp, err := s.engine(group)
+		if err != nil {
+			return err
+		}
+
+		if err := s.registerRouters(grp, group, routers, middlewares...); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.routes += count
+		s.mu.Unlock()
+		return nil
+	}
+
+	if err := register(); err != nil {
+		return err
+	}
+	s.recordReplay(register)
+	return nil
+}
+
+// engine returns the echo instance for ROOT or the group for kind. Groups
+// are created once and reused, so middlewares added by one RegisterRouters
+// call also cover the routes of later calls for the same group. The scoped
+// middlewares are installed only when the engine is first used.
+func (s *Server) engine(kind Kind) (any, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	switch group {
+	switch kind {
 	case ROOT:
-		grp = s.echo
+		// ROOT routes live on the echo instance itself, so its scoped
+		// middlewares wrap each route instead of going through Use, which
+		// would cover every group too
+		if s.rootScoped == nil {
+			s.rootScoped = append([]MiddlewareFunc{}, s.scoped(ROOT)...)
+		}
+		return s.echo, nil
 	case V1, V2, V3, DEV, API, DOCS:
-		grp = s.echo.Group(group.String())
+		g, ok := s.groups[kind]
+		if !ok {
+			g = s.echo.Group(kind.String(), s.scoped(kind)...)
+			s.groups[kind] = g
+		}
+		return g, nil
 	default:
-		return fmt.Errorf("invalid group type")
+		return nil, fmt.Errorf("invalid group type")
 	}
+}
 
-	return s.registerRouters(grp, routers, middlewares...)
+// Group returns the echo group RegisterRouters uses for kind, creating it
+// if needed, so nested groups such as /v1/admin can be built on it with
+// their own middlewares. ROOT returns a group without prefix on the echo
+// instance carrying the ROOT scoped middlewares. It returns nil for an
+// invalid kind.
+func (s *Server) Group(kind Kind) *echo.Group {
+	engine, err := s.engine(kind)
+	if err != nil {
+		return nil
+	}
+
+	if g, ok := engine.(*echo.Group); ok {
+		return g
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.rootGroup == nil {
+		s.rootGroup = s.echo.Group("", s.rootScoped...)
+	}
+	return s.rootGroup
 }
 
-// registerRouters registers routers to the given Echo group or instance
-func (s *Server) registerRouters(engine any, routers *RegisterRouters, middlewares ...MiddlewareFunc) error {
-	for _, middleware := range middlewares {
-		switch e := engine.(type) {
-		case *echo.Group:
-			e.Use(middleware)
-		case *echo.Echo:
-			e.Use(middleware)
+// scoped returns the middlewares configured for a group through options.
+// Group errors go first so auth and transform errors are rendered too.
+func (s *Server) scoped(kind Kind) []MiddlewareFunc {
+	params := s.params
+
+	mws := params.groupErrors(kind)
+	mws = append(mws, groupScoped(params.GetAuth(), kind)...)
+	mws = append(mws, groupScoped(params.GetRequireCorrelationID(), kind)...)
+	if params.GetReplayStore() != nil {
+		for _, g := range params.GetReplayGroups() {
+			if g == kind {
+				mws = append(mws, s.rejectReplays())
+				break
+			}
 		}
 	}
+	mws = append(mws, params.groupTransforms(kind)...)
+	return append(mws, params.GetGroupMiddlewares()[kind]...)
+}
+
+// RegisterRoutersWithPrefix registers multiple routers under a custom path
+// prefix such as "/internal", for groups the Kind enum doesn't cover. Routes
+// are listed with the ROOT group and only global auth applies to them.
+func (s *Server) RegisterRoutersWithPrefix(prefix string, routers *RegisterRouters, middlewares ...MiddlewareFunc) error {
+	prefix = strings.TrimSuffix(strings.TrimSpace(prefix), "/")
+	if !strings.HasPrefix(prefix, "/") {
+		return fmt.Errorf("invalid group prefix: %q", prefix)
+	}
+
+	register := func() error {
+		count, err := s.checkLimits(routers)
+		if err != nil {
+			return err
+		}
+
+		if err := s.registerRouters(s.echo.Group(prefix), ROOT, routers, middlewares...); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.routes += count
+		s.mu.Unlock()
+		return nil
+	}
+
+	if err := register(); err != nil {
+		return err
+	}
+	s.recordReplay(register)
+	return nil
+}
+
+// checkLimits validates routers against WithMaxRoutes, returning how many
+// routes they add. The built-in health, metrics and admin endpoints don't
+// count toward the limit.
+func (s *Server) checkLimits(routers *RegisterRouters) (int, error) {
+	count := 0
+	for _, router := range routers.GetAllRouters() {
+		if !router.builtin {
+			count += len(router.Methods)
+		}
+	}
+
+	s.mu.RLock()
+	routes := s.routes
+	s.mu.RUnlock()
+
+	if limit := s.params.GetMaxRoutes(); limit > 0 && routes+count > limit {
+		return 0, fmt.Errorf("route limit exceeded: %d registered, %d new, max %d", routes, count, limit)
+	}
+
+	return count, nil
+}
+
+// RegisterGone registers a retired endpoint answering 410 Gone with the given message for every method
+func (s *Server) RegisterGone(group Kind, path, message string) error {
+	gone := func(c Context) error {
+		return echo.NewHTTPError(http.StatusGone, message)
+	}
+
+	methods := Methods{}
+	for _, method := range []string{
+		http.MethodGet, http.MethodPost, http.MethodPut,
+		http.MethodDelete, http.MethodPatch, http.MethodHead,
+		http.MethodConnect, http.MethodOptions, http.MethodTrace,
+	} {
+		methods[method] = gone
+	}
+
+	rr := NewRouters()
+	if err := rr.AddRouter(path, methods); err != nil {
+		return err
+	}
+
+	return s.RegisterRouters(group, rr)
+}
 
-	for _, methods := range routers.GetAllRouters() {
-		for method, handler := range methods.Methods {
-			if err := s.registerMethod(engine, method, methods.Path, handler); err != nil {
+// registerRouters registers routers to the given Echo group or instance
+func (s *Server) registerRouters(engine any, group Kind, routers *RegisterRouters, middlewares ...MiddlewareFunc) error {
+	// groups are shared across calls, so the middlewares of this call wrap
+	// its own routes only; use WithGroupMiddleware to cover a whole group
+	callMws := middlewares
+	if _, ok := engine.(*echo.Echo); ok {
+		callMws = append(append([]MiddlewareFunc{}, s.rootScoped...), middlewares...)
+	}
+
+	// register in path then method order so route listings are reproducible
+	sorted := append([]RegisterRouter(nil), routers.GetAllRouters()...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Path < sorted[j].Path })
+
+	for _, methods := range sorted {
+		if methods.DevOnly && group != DEV {
+			continue
+		}
+		if methods.WebSocket != nil {
+			methods.Methods = Methods{http.MethodGet: s.webSocket(methods.WebSocket)}
+		}
+
+		names := make([]string, 0, len(methods.Methods))
+		for method := range methods.Methods {
+			names = append(names, method)
+		}
+		sort.Strings(names)
+
+		for _, method := range names {
+			// keep nil handlers out of echo so they fail Validate instead
+			// of panicking on the first request
+			if methods.Methods[method] == nil {
+				s.mu.Lock()
+				s.invalid = append(s.invalid, fmt.Errorf("nil handler for %s %s", method, methods.Path))
+				s.mu.Unlock()
+				continue
+			}
+
+			mws := append([]MiddlewareFunc(nil), callMws...)
+			mws = append(mws, methods.Middlewares...)
+			mws = append(mws, methods.MethodMiddlewares[method]...)
+			route, err := s.registerMethod(engine, method, methods.Path, methods.Methods[method], mws...)
+			if err != nil {
 				return err
 			}
+
+			s.mu.Lock()
+			s.registry = append(s.registry, registeredRoute{
+				RouteEntry: RouteEntry{
+					Group:   group,
+					Method:  route.Method,
+					Path:    route.Path,
+					Summary: methods.Summary,
+				},
+				name:   route.Name,
+				hidden: methods.Hidden,
+			})
+			if methods.NoCompression {
+				s.noCompression[route.Method+" "+route.Path] = true
+			}
+			if methods.builtin {
+				s.builtin[route.Method+" "+route.Path] = true
+			}
+			s.mu.Unlock()
 		}
 	}
 
 	return nil
 }
 
-// registerMethod registers a single method to the Echo instance
-func (s *Server) registerMethod(engine any, method, path string, handler echo.HandlerFunc) error {
+// registerMethod registers a single method to the Echo instance, wrapping
+// the handler in the given route-level middlewares
+func (s *Server) registerMethod(engine any, method, path string, handler echo.HandlerFunc, middlewares ...MiddlewareFunc) (*Route, error) {
+	var route *Route
+
 	switch e := engine.(type) {
 	case *echo.Group:
 		switch method {
 		case http.MethodGet:
-			e.GET(path, handler)
+			route = e.GET(path, handler, middlewares...)
 		case http.MethodPost:
-			e.POST(path, handler)
+			route = e.POST(path, handler, middlewares...)
 		case http.MethodPut:
-			e.PUT(path, handler)
+			route = e.PUT(path, handler, middlewares...)
 		case http.MethodDelete:
-			e.DELETE(path, handler)
+			route = e.DELETE(path, handler, middlewares...)
 		case http.MethodPatch:
-			e.PATCH(path, handler)
+			route = e.PATCH(path, handler, middlewares...)
 		case http.MethodHead:
-			e.HEAD(path, handler)
+			route = e.HEAD(path, handler, middlewares...)
 		case http.MethodConnect:
-			e.CONNECT(path, handler)
+			route = e.CONNECT(path, handler, middlewares...)
 		case http.MethodOptions:
-			e.OPTIONS(path, handler)
+			route = e.OPTIONS(path, handler, middlewares...)
 		case http.MethodTrace:
-			e.TRACE(path, handler)
+			route = e.TRACE(path, handler, middlewares...)
 		default:
-			return fmt.Errorf("unsupported method: %s", method)
+			return nil, fmt.Errorf("unsupported method: %s", method)
 		}
 
 	case *echo.Echo:
 		switch method {
 		case http.MethodGet:
-			e.GET(path, handler)
+			route = e.GET(path, handler, middlewares...)
 		case http.MethodPost:
-			e.POST(path, handler)
+			route = e.POST(path, handler, middlewares...)
 		case http.MethodPut:
-			e.PUT(path, handler)
+			route = e.PUT(path, handler, middlewares...)
 		case http.MethodDelete:
-			e.DELETE(path, handler)
+			route = e.DELETE(path, handler, middlewares...)
 		case http.MethodPatch:
-			e.PATCH(path, handler)
+			route = e.PATCH(path, handler, middlewares...)
 		case http.MethodHead:
-			e.HEAD(path, handler)
+			route = e.HEAD(path, handler, middlewares...)
 		case http.MethodConnect:
-			e.CONNECT(path, handler)
+			route = e.CONNECT(path, handler, middlewares...)
 		case http.MethodOptions:
-			e.OPTIONS(path, handler)
+			route = e.OPTIONS(path, handler, middlewares...)
 		case http.MethodTrace:
-			e.TRACE(path, handler)
+			route = e.TRACE(path, handler, middlewares...)
 		default:
-			return fmt.Errorf("unsupported method: %s", method)
+			return nil, fmt.Errorf("unsupported method: %s", method)
 		}
 	default:
-		return fmt.Errorf("engine type not supported")
+		return nil, fmt.Errorf("engine type not supported")
 	}
 
-	return nil
+	return route, nil
 }
 
-// Start starts the server
+// Start starts the server in the background and exits the process if it
+// cannot bind or serve; use StartListening to handle those errors
 func (s *Server) Start() {
-	host := fmt.Sprintf("%s:%s", s.host, s.port)
-	if len(s.port) == 0 {
-		host = s.host
+	if err := s.listen(); err != nil {
+		if errors.Is(err, ErrServerStarted) || errors.Is(err, ErrServerStopped) {
+			s.front.Logger.Warn(err)
+			return
+		}
+		s.front.Logger.Fatal(err)
 	}
 
 	go func() {
-		if err := s.echo.Start(host); err != nil && err != http.ErrServerClosed {
-			s.echo.Logger.Fatal(err)
+		if err := s.serve(); err != nil {
+			s.front.Logger.Fatal(err)
 		}
 	}()
+
+	go s.runStartHooks()
+}
+
+// StartListening binds the listener and serves until the server is shut
+// down, returning bind and serve errors to the caller instead of exiting.
+// It returns nil after a graceful shutdown.
+func (s *Server) StartListening() error {
+	if err := s.listen(); err != nil {
+		return err
+	}
+
+	go s.runStartHooks()
+
+	return s.serve()
 }
 
-// GetEcho returns the Echo instance
+// Addr returns the address the server listens on. Before Start, and once
+// the server is shut down, there is no listener and it returns an empty
+// string instead of blocking; wait on Listening to get the bound address.
+func (s *Server) Addr() string {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	if s.state != stateRunning {
+		return ""
+	}
+
+	addr := s.front.ListenerAddr()
+	if s.params.useTLS() {
+		addr = s.front.TLSListenerAddr()
+	}
+	if addr == nil {
+		return ""
+	}
+	return addr.String()
+}
+
+// Started reports whether the server is listening. Requests can still be
+// served through the echo instance before Start, e.g. in tests, but
+// readiness and admin endpoints answer 503 until it returns true.
+func (s *Server) Started() bool {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+	return s.state == stateRunning
+}
+
+// Listening returns a channel closed once the listener is bound and the
+// server accepts connections
+func (s *Server) Listening() <-chan struct{} {
+	return s.listening
+}
+
+// address returns the host:port the server listens on
+func (s *Server) address() string {
+	if len(s.port) == 0 {
+		return s.host
+	}
+	return fmt.Sprintf("%s:%s", s.host, s.port)
+}
+
+// listen binds the listener unless one was set on the echo instance. It
+// fails if the server was already started or shut down, or if Validate
+// reports invalid routes.
+func (s *Server) listen() error {
+	s.lifecycle.Lock()
+	defer s.lifecycle.Unlock()
+
+	switch s.state {
+	case stateRunning:
+		return ErrServerStarted
+	case stateStopped:
+		return ErrServerStopped
+	}
+
+	if err := s.Validate(); err != nil {
+		return err
+	}
+
+	tlsConfig, err := s.serverTLSConfig()
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case tlsConfig != nil:
+		s.front.TLSServer.TLSConfig = tlsConfig
+		if s.front.TLSListener == nil {
+			l, err := s.bind()
+			if err != nil {
+				return err
+			}
+			s.front.TLSListener = tls.NewListener(l, tlsConfig)
+		}
+	case s.front.Listener == nil:
+		l, err := s.bind()
+		if err != nil {
+			return err
+		}
+		s.front.Listener = l
+	}
+
+	s.state = stateRunning
+	s.listenOnce.Do(func() { close(s.listening) })
+	return nil
+}
+
+// bind opens the TCP listener, or takes the one given with WithListener,
+// capped by WithMaxConnections
+func (s *Server) bind() (net.Listener, error) {
+	l := s.params.GetListener()
+	if l == nil {
+		var err error
+		if l, err = net.Listen("tcp", s.address()); err != nil {
+			return nil, err
+		}
+	}
+	if n := s.params.GetMaxConnections(); n > 0 {
+		l = netutil.LimitListener(l, n)
+	}
+	return l, nil
+}
+
+// serve serves on the bound listener, treating a closed server as success
+func (s *Server) serve() error {
+	var err error
+	if s.params.useTLS() {
+		s.front.TLSServer.Addr = s.address()
+		err = s.front.StartServer(s.front.TLSServer)
+	} else {
+		err = s.front.Start(s.address())
+	}
+
+	if err != nil && err != http.ErrServerClosed {
+		return err
+	}
+	return nil
+}
+
+// RunWithSignals starts the server and blocks until a shutdown signal is
+// received or ctx is cancelled, then shuts the server down gracefully. The
+// signals are SIGINT and SIGTERM unless set with WithShutdownSignals. It
+// returns bind, serve and shutdown errors.
+func (s *Server) RunWithSignals(ctx context.Context) error {
+	sigs := s.params.GetShutdownSignals()
+	if len(sigs) == 0 {
+		sigs = []os.Signal{syscall.SIGINT, syscall.SIGTERM}
+	}
+
+	ctx, stop := signal.NotifyContext(ctx, sigs...)
+	defer stop()
+
+	served := make(chan error, 1)
+	go func() { served <- s.StartListening() }()
+
+	select {
+	case err := <-served:
+		return err
+	case <-ctx.Done():
+	}
+	stop()
+
+	if err := s.GracefulShutdown(); err != nil {
+		return err
+	}
+	return <-served
+}
+
+// OnStart registers a hook run once the server starts; the server is not
+// ready until every hook has returned without error
+func (s *Server) OnStart(fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.startHooks = append(s.startHooks, fn)
+}
+
+// RegisterOnShutdown registers a cleanup function, e.g. closing a database
+// pool, run by Shutdown once in-flight requests have drained. Functions run
+// in reverse registration order and all of them run even if one fails.
+func (s *Server) RegisterOnShutdown(fn func(ctx context.Context) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.shutdownHooks = append(s.shutdownHooks, fn)
+}
+
+// runShutdownHooks runs the shutdown hooks last registered first, joining
+// their errors
+func (s *Server) runShutdownHooks(ctx context.Context) error {
+	s.mu.Lock()
+	hooks := append([]func(ctx context.Context) error(nil), s.shutdownHooks...)
+	s.mu.Unlock()
+
+	var errs []error
+	for i := len(hooks) - 1; i >= 0; i-- {
+		if err := hooks[i](ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// runStartHooks runs the start hooks in registration order, stopping at the
+// first failure
+func (s *Server) runStartHooks() {
+	s.mu.Lock()
+	hooks := append([]func(ctx context.Context) error(nil), s.startHooks...)
+	s.mu.Unlock()
+
+	for _, hook := range hooks {
+		if err := hook(context.Background()); err != nil {
+			s.front.Logger.Errorf("start hook failed: %v", err)
+			return
+		}
+	}
+
+	s.hooksDone.Store(true)
+}
+
+// MarkReady flags the server as ready to receive traffic
+func (s *Server) MarkReady() {
+	s.ready.Store(true)
+}
+
+// IsReady reports whether the server is running and not shutting down, the
+// start hooks completed and MarkReady was called
+func (s *Server) IsReady() bool {
+	return s.Started() && !s.draining.Load() && s.hooksDone.Load() && s.ready.Load()
+}
+
+// ReadinessHandler returns a handler answering 200 when the server is ready
+// and 503 otherwise
+func (s *Server) ReadinessHandler() HandlerFunc {
+	return func(c Context) error {
+		if !s.IsReady() {
+			return c.String(http.StatusServiceUnavailable, "not ready")
+		}
+		return c.String(http.StatusOK, "ready")
+	}
+}
+
+// GetEcho returns the Echo instance serving requests, which changes when
+// ResetRouters runs
 func (s *Server) GetEcho() *echo.Echo {
-	return s.echo
+	return s.live.Load()
 }
 
-// GetRouters returns all registered routes
+// GetRouters returns all registered routes sorted by path then method
 func (s *Server) GetRouters() []*Route {
-	return s.echo.Routes()
+	routes := s.live.Load().Routes()
+	sort.Slice(routes, func(i, j int) bool {
+		if routes[i].Path != routes[j].Path {
+			return routes[i].Path < routes[j].Path
+		}
+		return routes[i].Method < routes[j].Method
+	})
+	return routes
 }
 
-// Close closes the server
+// Close stops the server immediately, dropping in-flight requests. Closing
+// a server that never started is a no-op and it can still be started, and
+// closing it again is a no-op. It may be called while Shutdown is draining
+// to cut it short.
 func (s *Server) Close() error {
-	return s.echo.Close()
+	s.lifecycle.Lock()
+	state, closed := s.state, s.closed
+	if state != stateNew {
+		s.state = stateStopped
+		s.closed = true
+	}
+	s.lifecycle.Unlock()
+
+	if state == stateNew || closed {
+		return nil
+	}
+
+	s.draining.Store(true)
+	s.cancelBase()
+	s.sockets.closeAll()
+	return s.front.Close()
 }
 
-// Shutdown gracefully shuts down the server
+// Shutdown gracefully shuts down the server. When ctx has a deadline, the
+// contexts of in-flight requests are cancelled a grace period before it so
+// handlers can bail out instead of being cut off. The grace is capped at
+// half the time left, so a short deadline doesn't cancel them right away.
+// The RegisterOnShutdown functions run once the server has drained and
+// their errors are returned along with the shutdown error. Open WebSocket
+// connections are closed with a going away status. Shutting down a server
+// that is not running is a no-op, and one that never started can still be
+// started afterwards.
 func (s *Server) Shutdown(ctx context.Context) error {
-	return s.echo.Shutdown(ctx)
+	s.lifecycle.Lock()
+	state := s.state
+	if state != stateNew {
+		s.state = stateStopped
+	}
+	s.lifecycle.Unlock()
+
+	// nothing to drain when the server never started or is already stopping
+	if state != stateRunning {
+		return nil
+	}
+
+	s.draining.Store(true)
+	s.sockets.closeAll()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		grace := s.params.GetShutdownGrace()
+		if grace == 0 {
+			grace = defaultShutdownGrace
+		}
+		if left := time.Until(deadline); grace > left/2 {
+			grace = left / 2
+		}
+		timer := time.AfterFunc(time.Until(deadline.Add(-grace)), s.cancelBase)
+		defer timer.Stop()
+	}
+
+	err := s.front.Shutdown(ctx)
+	if err != nil {
+		s.cancelBase()
+	}
+
+	return errors.Join(err, s.runShutdownHooks(ctx))
 }
 
-// GracefulShutdown shuts down the server with a timeout
+// GracefulShutdown shuts down the server within the WithShutdownTimeout
+// timeout, 3 seconds by default. With WithPreShutdownDelay, readiness
+// answers 503 first and requests keep being served for the delay, giving
+// load balancers time to deregister the instance before it drains. The
+// delay comes on top of the timeout and calling Close cuts it short.
 func (s *Server) GracefulShutdown() error {
 	return s.gracefulShutdown()
 }
 
 func (s *Server) gracefulShutdown() error {
-	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	timeout := s.params.GetShutdownTimeout()
+	if timeout == 0 {
+		timeout = defaultShutdownTimeout
+	}
+	delay := s.params.GetPreShutdownDelay()
+
+	ctx, cancel := context.WithTimeout(context.Background(), delay+timeout)
 	defer cancel()
+
+	if delay > 0 && s.Started() {
+		s.draining.Store(true)
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+		case <-s.baseCtx.Done():
+		}
+		timer.Stop()
+	}
+
 	return s.Shutdown(ctx)
 }
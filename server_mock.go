@@ -14,7 +14,7 @@ import (
 	http "net/http"
 	reflect "reflect"
 
-	v4 "github.com/labstack/echo/v4"
+	echo "github.com/labstack/echo/v4"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -41,6 +41,20 @@ func (m *MockServerRepo) EXPECT() *MockServerRepoMockRecorder {
 	return m.recorder
 }
 
+// Addr mocks base method.
+func (m *MockServerRepo) Addr() string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Addr")
+	ret0, _ := ret[0].(string)
+	return ret0
+}
+
+// Addr indicates an expected call of Addr.
+func (mr *MockServerRepoMockRecorder) Addr() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Addr", reflect.TypeOf((*MockServerRepo)(nil).Addr))
+}
+
 // Close mocks base method.
 func (m *MockServerRepo) Close() error {
 	m.ctrl.T.Helper()
@@ -56,10 +70,10 @@ func (mr *MockServerRepoMockRecorder) Close() *gomock.Call {
 }
 
 // GetEcho mocks base method.
-func (m *MockServerRepo) GetEcho() *v4.Echo {
+func (m *MockServerRepo) GetEcho() *echo.Echo {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetEcho")
-	ret0, _ := ret[0].(*v4.Echo)
+	ret0, _ := ret[0].(*echo.Echo)
 	return ret0
 }
 
@@ -97,6 +111,130 @@ func (mr *MockServerRepoMockRecorder) GracefulShutdown() *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GracefulShutdown", reflect.TypeOf((*MockServerRepo)(nil).GracefulShutdown))
 }
 
+// Group mocks base method.
+func (m *MockServerRepo) Group(kind Kind) *echo.Group {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Group", kind)
+	ret0, _ := ret[0].(*echo.Group)
+	return ret0
+}
+
+// Group indicates an expected call of Group.
+func (mr *MockServerRepoMockRecorder) Group(kind any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Group", reflect.TypeOf((*MockServerRepo)(nil).Group), kind)
+}
+
+// IsReady mocks base method.
+func (m *MockServerRepo) IsReady() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "IsReady")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// IsReady indicates an expected call of IsReady.
+func (mr *MockServerRepoMockRecorder) IsReady() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsReady", reflect.TypeOf((*MockServerRepo)(nil).IsReady))
+}
+
+// Listening mocks base method.
+func (m *MockServerRepo) Listening() <-chan struct{} {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Listening")
+	ret0, _ := ret[0].(<-chan struct{})
+	return ret0
+}
+
+// Listening indicates an expected call of Listening.
+func (mr *MockServerRepoMockRecorder) Listening() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Listening", reflect.TypeOf((*MockServerRepo)(nil).Listening))
+}
+
+// MarkReady mocks base method.
+func (m *MockServerRepo) MarkReady() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "MarkReady")
+}
+
+// MarkReady indicates an expected call of MarkReady.
+func (mr *MockServerRepoMockRecorder) MarkReady() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MarkReady", reflect.TypeOf((*MockServerRepo)(nil).MarkReady))
+}
+
+// MiddlewareCors mocks base method.
+func (m *MockServerRepo) MiddlewareCors() MiddlewareFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MiddlewareCors")
+	ret0, _ := ret[0].(MiddlewareFunc)
+	return ret0
+}
+
+// MiddlewareCors indicates an expected call of MiddlewareCors.
+func (mr *MockServerRepoMockRecorder) MiddlewareCors() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MiddlewareCors", reflect.TypeOf((*MockServerRepo)(nil).MiddlewareCors))
+}
+
+// MiddlewareLogger mocks base method.
+func (m *MockServerRepo) MiddlewareLogger() MiddlewareFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MiddlewareLogger")
+	ret0, _ := ret[0].(MiddlewareFunc)
+	return ret0
+}
+
+// MiddlewareLogger indicates an expected call of MiddlewareLogger.
+func (mr *MockServerRepoMockRecorder) MiddlewareLogger() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MiddlewareLogger", reflect.TypeOf((*MockServerRepo)(nil).MiddlewareLogger))
+}
+
+// MiddlewareRecover mocks base method.
+func (m *MockServerRepo) MiddlewareRecover() MiddlewareFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MiddlewareRecover")
+	ret0, _ := ret[0].(MiddlewareFunc)
+	return ret0
+}
+
+// MiddlewareRecover indicates an expected call of MiddlewareRecover.
+func (mr *MockServerRepoMockRecorder) MiddlewareRecover() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MiddlewareRecover", reflect.TypeOf((*MockServerRepo)(nil).MiddlewareRecover))
+}
+
+// Middlewares mocks base method.
+func (m *MockServerRepo) Middlewares() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Middlewares")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// Middlewares indicates an expected call of Middlewares.
+func (mr *MockServerRepoMockRecorder) Middlewares() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Middlewares", reflect.TypeOf((*MockServerRepo)(nil).Middlewares))
+}
+
+// MiddlewaresHandler mocks base method.
+func (m *MockServerRepo) MiddlewaresHandler() HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "MiddlewaresHandler")
+	ret0, _ := ret[0].(HandlerFunc)
+	return ret0
+}
+
+// MiddlewaresHandler indicates an expected call of MiddlewaresHandler.
+func (mr *MockServerRepoMockRecorder) MiddlewaresHandler() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "MiddlewaresHandler", reflect.TypeOf((*MockServerRepo)(nil).MiddlewaresHandler))
+}
+
 // NewContext mocks base method.
 func (m *MockServerRepo) NewContext(req *http.Request, w http.ResponseWriter) Context {
 	m.ctrl.T.Helper()
@@ -111,6 +249,58 @@ func (mr *MockServerRepoMockRecorder) NewContext(req, w any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "NewContext", reflect.TypeOf((*MockServerRepo)(nil).NewContext), req, w)
 }
 
+// OnStart mocks base method.
+func (m *MockServerRepo) OnStart(fn func(context.Context) error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "OnStart", fn)
+}
+
+// OnStart indicates an expected call of OnStart.
+func (mr *MockServerRepoMockRecorder) OnStart(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "OnStart", reflect.TypeOf((*MockServerRepo)(nil).OnStart), fn)
+}
+
+// ReadinessHandler mocks base method.
+func (m *MockServerRepo) ReadinessHandler() HandlerFunc {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ReadinessHandler")
+	ret0, _ := ret[0].(HandlerFunc)
+	return ret0
+}
+
+// ReadinessHandler indicates an expected call of ReadinessHandler.
+func (mr *MockServerRepoMockRecorder) ReadinessHandler() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ReadinessHandler", reflect.TypeOf((*MockServerRepo)(nil).ReadinessHandler))
+}
+
+// RegisterGone mocks base method.
+func (m *MockServerRepo) RegisterGone(group Kind, path, message string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RegisterGone", group, path, message)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterGone indicates an expected call of RegisterGone.
+func (mr *MockServerRepoMockRecorder) RegisterGone(group, path, message any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterGone", reflect.TypeOf((*MockServerRepo)(nil).RegisterGone), group, path, message)
+}
+
+// RegisterOnShutdown mocks base method.
+func (m *MockServerRepo) RegisterOnShutdown(fn func(context.Context) error) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "RegisterOnShutdown", fn)
+}
+
+// RegisterOnShutdown indicates an expected call of RegisterOnShutdown.
+func (mr *MockServerRepoMockRecorder) RegisterOnShutdown(fn any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterOnShutdown", reflect.TypeOf((*MockServerRepo)(nil).RegisterOnShutdown), fn)
+}
+
 // RegisterRouters mocks base method.
 func (m *MockServerRepo) RegisterRouters(group Kind, routers *RegisterRouters, middlewares ...MiddlewareFunc) error {
 	m.ctrl.T.Helper()
@@ -130,6 +320,39 @@ func (mr *MockServerRepoMockRecorder) RegisterRouters(group, routers any, middle
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRouters", reflect.TypeOf((*MockServerRepo)(nil).RegisterRouters), varargs...)
 }
 
+// RegisterRoutersWithPrefix mocks base method.
+func (m *MockServerRepo) RegisterRoutersWithPrefix(prefix string, routers *RegisterRouters, middlewares ...MiddlewareFunc) error {
+	m.ctrl.T.Helper()
+	varargs := []any{prefix, routers}
+	for _, a := range middlewares {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "RegisterRoutersWithPrefix", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RegisterRoutersWithPrefix indicates an expected call of RegisterRoutersWithPrefix.
+func (mr *MockServerRepoMockRecorder) RegisterRoutersWithPrefix(prefix, routers any, middlewares ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{prefix, routers}, middlewares...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RegisterRoutersWithPrefix", reflect.TypeOf((*MockServerRepo)(nil).RegisterRoutersWithPrefix), varargs...)
+}
+
+// RunWithSignals mocks base method.
+func (m *MockServerRepo) RunWithSignals(ctx context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RunWithSignals", ctx)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// RunWithSignals indicates an expected call of RunWithSignals.
+func (mr *MockServerRepoMockRecorder) RunWithSignals(ctx any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RunWithSignals", reflect.TypeOf((*MockServerRepo)(nil).RunWithSignals), ctx)
+}
+
 // Shutdown mocks base method.
 func (m *MockServerRepo) Shutdown(ctx context.Context) error {
 	m.ctrl.T.Helper()
@@ -155,3 +378,59 @@ func (mr *MockServerRepoMockRecorder) Start() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Start", reflect.TypeOf((*MockServerRepo)(nil).Start))
 }
+
+// StartListening mocks base method.
+func (m *MockServerRepo) StartListening() error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartListening")
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartListening indicates an expected call of StartListening.
+func (mr *MockServerRepoMockRecorder) StartListening() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartListening", reflect.TypeOf((*MockServerRepo)(nil).StartListening))
+}
+
+// Started mocks base method.
+func (m *MockServerRepo) Started() bool {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Started")
+	ret0, _ := ret[0].(bool)
+	return ret0
+}
+
+// Started indicates an expected call of Started.
+func (mr *MockServerRepoMockRecorder) Started() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Started", reflect.TypeOf((*MockServerRepo)(nil).Started))
+}
+
+// Use mocks base method.
+func (m *MockServerRepo) Use(middleware MiddlewareFunc) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Use", middleware)
+}
+
+// Use indicates an expected call of Use.
+func (mr *MockServerRepoMockRecorder) Use(middleware any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Use", reflect.TypeOf((*MockServerRepo)(nil).Use), middleware)
+}
+
+// Uses mocks base method.
+func (m *MockServerRepo) Uses(middlewares ...MiddlewareFunc) {
+	m.ctrl.T.Helper()
+	varargs := []any{}
+	for _, a := range middlewares {
+		varargs = append(varargs, a)
+	}
+	m.ctrl.Call(m, "Uses", varargs...)
+}
+
+// Uses indicates an expected call of Uses.
+func (mr *MockServerRepoMockRecorder) Uses(middlewares ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Uses", reflect.TypeOf((*MockServerRepo)(nil).Uses), middlewares...)
+}
@@ -1,10 +1,18 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"context"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
 	"testing"
 	"time"
 
@@ -43,19 +51,90 @@ func TestRegisterRouters(t *testing.T) {
 func TestStartAndShutdown(t *testing.T) {
 	server, _ := NewServer()
 
-	go server.Start()
-	time.Sleep(1 * time.Second)
+	server.Start()
+	<-server.Listening()
 
 	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
 	defer cancel()
 	assert.NoError(t, server.Shutdown(ctx))
 }
 
+func TestStartListening(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	done := make(chan error, 1)
+	go func() { done <- server.StartListening() }()
+
+	select {
+	case <-server.Listening():
+	case err := <-done:
+		t.Fatalf("server failed to start: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	assert.NoError(t, server.Shutdown(ctx))
+	assert.NoError(t, <-done)
+}
+
+func TestStartListeningAddressInUse(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort(port))
+
+	err = server.StartListening()
+	assert.ErrorContains(t, err, "address already in use")
+
+	select {
+	case <-server.Listening():
+		t.Fatal("listening signalled for a failed bind")
+	default:
+	}
+}
+
+func TestWithListener(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	server, _ := NewServer(WithListener(l), WithMaxConnections(4))
+	rr := NewRouters()
+	rr.AddRouter("/ping", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "pong")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	server.Start()
+	<-server.Listening()
+	assert.Equal(t, l.Addr().String(), server.Addr())
+
+	resp, err := http.Get("http://" + server.Addr() + "/ping")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		assert.Equal(t, "pong", string(body))
+	}
+
+	assert.NoError(t, server.GracefulShutdown())
+
+	// the server owns the listener once started
+	_, err = l.Accept()
+	assert.ErrorIs(t, err, net.ErrClosed)
+}
+
 func TestServerClose(t *testing.T) {
 	server, _ := NewServer()
 
-	go server.Start()
-	time.Sleep(1 * time.Second)
+	server.Start()
+	<-server.Listening()
 
 	assert.NoError(t, server.Close())
 }
@@ -69,8 +148,8 @@ func TestGetEcho(t *testing.T) {
 func TestGracefulShutdown(t *testing.T) {
 	server, _ := NewServer()
 
-	go server.Start()
-	time.Sleep(1 * time.Second)
+	server.Start()
+	<-server.Listening()
 
 	assert.NoError(t, server.gracefulShutdown())
 }
@@ -90,6 +169,66 @@ func TestGetRouters(t *testing.T) {
 	assert.Len(t, routers, 1)
 }
 
+func TestAddRouterDuplicate(t *testing.T) {
+	handler := func(c Context) error { return nil }
+
+	rr := NewRouters()
+	rr.SetPathFixed("/users")
+
+	assert.NoError(t, rr.AddRouter("/users", Methods{http.MethodGet: handler}))
+	assert.NoError(t, rr.AddRouter("/users", Methods{http.MethodPost: handler}))
+	assert.EqualError(t, rr.AddRouter("/users", Methods{http.MethodGet: handler}), "duplicate route: GET /users")
+	assert.Error(t, rr.AddRouterFx("", Methods{http.MethodPost: handler}))
+	assert.Error(t, rr.AddRoute(RegisterRouter{Path: "/users", Methods: Methods{http.MethodGet: handler}}))
+	assert.NoError(t, rr.AddRouterFx("/:id", Methods{http.MethodGet: handler}))
+
+	assert.Len(t, rr.GetAllRouters(), 3)
+	assert.Len(t, rr.GetRouters("/users"), 2)
+	assert.Len(t, rr.GetRouters("/users/:id"), 1)
+	assert.Empty(t, rr.GetRouters("/orders"))
+
+	// routers appended directly are picked up by the index
+	rr.Routers = append(rr.Routers, RegisterRouter{Path: "/orders", Methods: Methods{http.MethodGet: handler}})
+	assert.Len(t, rr.GetRouters("/orders"), 1)
+	assert.Error(t, rr.AddRouter("/orders", Methods{http.MethodGet: handler}))
+}
+
+func TestGetRoutersDeterministicOrder(t *testing.T) {
+	handler := func(c Context) error { return nil }
+	methods := Methods{
+		http.MethodPut:    handler,
+		http.MethodGet:    handler,
+		http.MethodDelete: handler,
+		http.MethodPost:   handler,
+	}
+
+	var first []string
+	for run := 0; run < 5; run++ {
+		server, _ := NewServer()
+		rr := NewRouters()
+		_ = rr.AddRouter("/users/:id", methods)
+		_ = rr.AddRouter("/articles", methods)
+		_ = rr.AddRouter("/users", methods)
+		_ = server.RegisterRouters(ROOT, rr)
+
+		var got []string
+		for _, route := range server.GetRouters() {
+			got = append(got, route.Method+" "+route.Path)
+		}
+
+		if run == 0 {
+			first = got
+			assert.Equal(t, []string{
+				"DELETE /articles", "GET /articles", "POST /articles", "PUT /articles",
+				"DELETE /users", "GET /users", "POST /users", "PUT /users",
+				"DELETE /users/:id", "GET /users/:id", "POST /users/:id", "PUT /users/:id",
+			}, got)
+			continue
+		}
+		assert.Equal(t, first, got)
+	}
+}
+
 func TestRegisterRoutes(t *testing.T) {
 	server, _ := NewServer()
 	rr := NewRouters()
@@ -957,7 +1096,7 @@ func TestInvalidEngineType(t *testing.T) {
 		},
 	})
 
-	err := server.registerRouters(nil, rr)
+	err := server.registerRouters(nil, ROOT, rr)
 	assert.Error(t, err)
 }
 
@@ -1021,8 +1160,8 @@ func TestSeverNewContext(t *testing.T) {
 func TestServerGracefulShutdown(t *testing.T) {
 	server, _ := NewServer()
 
-	go server.Start()
-	time.Sleep(1 * time.Second)
+	server.Start()
+	<-server.Listening()
 
 	assert.NoError(t, server.GracefulShutdown())
 }
@@ -1038,6 +1177,42 @@ func TestServerCloseReturnError(t *testing.T) {
 	assert.NoError(t, server.Close())
 }
 
+func TestServerCloseIdempotent(t *testing.T) {
+	// never started: both are no-ops and the server can still be started
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+	assert.NoError(t, server.Close())
+	assert.NoError(t, server.Close())
+	assert.NoError(t, server.GracefulShutdown())
+	server.Start()
+	<-server.Listening()
+	assert.NoError(t, server.Close())
+	assert.ErrorIs(t, server.StartListening(), ErrServerStopped)
+
+	// running: concurrent and repeated calls are safe
+	server, _ = NewServer(WithHost("127.0.0.1"), WithPort("0"))
+	server.Start()
+	<-server.Listening()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, server.Close())
+		}()
+		go func() {
+			defer wg.Done()
+			_ = server.GracefulShutdown()
+		}()
+	}
+	wg.Wait()
+
+	assert.NoError(t, server.Close())
+	assert.NoError(t, server.GracefulShutdown())
+	assert.False(t, server.Started())
+	assert.ErrorIs(t, server.StartListening(), ErrServerStopped)
+}
+
 func TestNewServerParamsWithNil(t *testing.T) {
 	params, err := newServerParams()
 	assert.NoError(t, err)
@@ -1193,3 +1368,1064 @@ func TestWithUses(t *testing.T) {
 	assert.Equal(t, http.StatusOK, rec.Code)
 	assert.Equal(t, "test passed", rec.Body.String())
 }
+
+func TestGracefulShutdownSetsConnectionClose(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	entered := make(chan struct{})
+	release := make(chan struct{})
+
+	rr := NewRouters()
+	rr.AddRouter("/slow", Methods{
+		http.MethodGet: func(c Context) error {
+			close(entered)
+			<-release
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	server.Start()
+
+	e := server.GetEcho()
+	<-server.Listening()
+
+	type result struct {
+		res *http.Response
+		err error
+	}
+	done := make(chan result, 1)
+	go func() {
+		res, err := http.Get("http://" + e.ListenerAddr().String() + "/slow")
+		done <- result{res, err}
+	}()
+
+	<-entered
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- server.GracefulShutdown() }()
+
+	assert.Eventually(t, server.draining.Load, time.Second, 10*time.Millisecond)
+	close(release)
+
+	r := <-done
+	if assert.NoError(t, r.err) {
+		defer r.res.Body.Close()
+		assert.Equal(t, http.StatusOK, r.res.StatusCode)
+		assert.True(t, r.res.Close)
+	}
+
+	assert.NoError(t, <-shutdown)
+}
+
+func TestReadinessGate(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	release := make(chan struct{})
+	server.OnStart(func(ctx context.Context) error {
+		<-release
+		return nil
+	})
+
+	rr := NewRouters()
+	rr.AddRouter("/ready", Methods{http.MethodGet: server.ReadinessHandler()})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+	readiness := func() int {
+		req := httptest.NewRequest(http.MethodGet, "/ready", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusServiceUnavailable, readiness())
+
+	server.Start()
+	defer server.Close()
+
+	server.MarkReady()
+	assert.Equal(t, http.StatusServiceUnavailable, readiness())
+
+	close(release)
+	assert.Eventually(t, server.IsReady, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, readiness())
+}
+
+func TestReadinessGateHookFailure(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	ran := make(chan struct{})
+	server.OnStart(func(ctx context.Context) error {
+		defer close(ran)
+		return errors.New("warmup failed")
+	})
+
+	server.Start()
+	defer server.Close()
+
+	<-ran
+	server.MarkReady()
+	assert.Never(t, server.IsReady, 100*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestMaxRoutes(t *testing.T) {
+	server, _ := NewServer(WithMaxRoutes(3))
+
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, "test passed")
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/a", Methods{http.MethodGet: handler, http.MethodPost: handler})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	rr = NewRouters()
+	rr.AddRouter("/b", Methods{http.MethodGet: handler})
+	rr.AddRouter("/c", Methods{http.MethodGet: handler})
+	assert.Error(t, server.RegisterRouters(V1, rr))
+
+	rr = NewRouters()
+	rr.AddRouter("/b", Methods{http.MethodGet: handler})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	rr = NewRouters()
+	rr.AddRouter("/d", Methods{http.MethodGet: handler})
+	assert.Error(t, server.RegisterRouters(ROOT, rr))
+
+	assert.Len(t, server.GetRouters(), 3)
+}
+
+func TestMaxRoutesExemptsBuiltinRoutes(t *testing.T) {
+	server, err := NewServer(WithMaxRoutes(1), WithAdmin("/_admin", adminToken))
+	assert.NoError(t, err)
+	assert.NoError(t, server.RegisterHealthChecks(HealthOptions{}))
+
+	handler := func(c Context) error {
+		return c.String(http.StatusOK, "test passed")
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/a", Methods{http.MethodGet: handler})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	rr = NewRouters()
+	rr.AddRouter("/b", Methods{http.MethodGet: handler})
+	assert.Error(t, server.RegisterRouters(ROOT, rr))
+}
+
+// Echo sizes its param storage from the registered routes, so routes with
+// many params work without any option
+func TestManyRouteParams(t *testing.T) {
+	server, _ := NewServer()
+
+	rr := NewRouters()
+	rr.AddRouter("/a/:p1/b/:p2/c/:p3/d/:p4/e/:p5/f/:p6/g/:p7/h/:p8/*", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.Param("p1")+" "+c.Param("p8")+" "+c.Param("*"))
+		},
+	})
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	req := httptest.NewRequest(http.MethodGet, "/a/1/b/2/c/3/d/4/e/5/f/6/g/7/h/8/rest/of/path", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "1 8 rest/of/path", rec.Body.String())
+}
+
+func TestRegisterGone(t *testing.T) {
+	server, _ := NewServer()
+
+	assert.NoError(t, server.RegisterGone(V1, "/legacy", "use /v2/users instead"))
+
+	e := server.GetEcho()
+	for _, method := range []string{http.MethodGet, http.MethodPost, http.MethodDelete} {
+		req := httptest.NewRequest(method, "/v1/legacy", nil)
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		assert.Equal(t, http.StatusGone, rec.Code)
+		assert.JSONEq(t, `{"message":"use /v2/users instead"}`, rec.Body.String())
+	}
+}
+
+func TestMaxConnections(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithMaxConnections(2))
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	server.Start()
+	defer server.Close()
+
+	addr := server.GetEcho().ListenerAddr().String()
+
+	get := func(conn net.Conn, timeout time.Duration) error {
+		if _, err := conn.Write([]byte("GET /test HTTP/1.1\r\nHost: test\r\n\r\n")); err != nil {
+			return err
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(timeout))
+		res, err := http.ReadResponse(bufio.NewReader(conn), nil)
+		if err != nil {
+			return err
+		}
+		return res.Body.Close()
+	}
+
+	var conns []net.Conn
+	for i := 0; i < 3; i++ {
+		conn, err := net.Dial("tcp", addr)
+		if !assert.NoError(t, err) {
+			return
+		}
+		defer conn.Close()
+		conns = append(conns, conn)
+	}
+
+	assert.NoError(t, get(conns[0], time.Second))
+	assert.NoError(t, get(conns[1], time.Second))
+
+	blocked := make(chan error, 1)
+	go func() { blocked <- get(conns[2], 3*time.Second) }()
+
+	select {
+	case err := <-blocked:
+		t.Fatalf("third connection served while limit reached: %v", err)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	conns[0].Close()
+	assert.NoError(t, <-blocked)
+}
+
+func TestMiddlewares(t *testing.T) {
+	server, _ := NewServer()
+	assert.Equal(t, []string{"close-on-drain", "unescape-params", "upload-cleanup"}, server.Middlewares())
+
+	server, _ = NewServer(
+		WithRecentRequests(10),
+		WithCanonicalHost("example.com"),
+		WithAccessLog(),
+		WithBodyDrain(1024),
+	)
+
+	expected := []string{"canonical-host", "close-on-drain", "unescape-params", "upload-cleanup", "access-log", "body-drain", "recent-requests"}
+	assert.Equal(t, expected, server.Middlewares())
+
+	rr := NewRouters()
+	rr.AddRouter("/middlewares", Methods{http.MethodGet: server.MiddlewaresHandler()})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/middlewares", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.JSONEq(t, `["canonical-host","close-on-drain","unescape-params","upload-cleanup","access-log","body-drain","recent-requests"]`, rec.Body.String())
+}
+
+func TestRouterFixedPathEncodedParams(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.SetPathFixed("/api")
+	rr.AddRouterFx("/files/:name/meta", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.Param("name"))
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Encoded slash is decoded", "/api/files/a%2Fb/meta", http.StatusOK, "a/b"},
+		{"Encoded space is decoded", "/api/files/a%20b/meta", http.StatusOK, "a b"},
+		{"Encoded percent is decoded once", "/api/files/a%2520b/meta", http.StatusOK, "a%20b"},
+		{"Mixed escapes are decoded once", "/api/files/a%2F%2520b/meta", http.StatusOK, "a/%20b"},
+		{"Literal slash is another path", "/api/files/a/b/meta", http.StatusNotFound, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+// shutdownCancellation shuts server down with the given timeout while a
+// request is in flight, returning the shutdown deadline and when the
+// request context was cancelled
+func shutdownCancellation(t *testing.T, server *Server, timeout time.Duration) (time.Time, time.Time) {
+	entered := make(chan struct{})
+	cancelled := make(chan time.Time, 1)
+
+	rr := NewRouters()
+	rr.AddRouter("/long", Methods{
+		http.MethodGet: func(c Context) error {
+			close(entered)
+			<-c.Request().Context().Done()
+			cancelled <- time.Now()
+			return c.NoContent(http.StatusServiceUnavailable)
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	server.Start()
+
+	e := server.GetEcho()
+	<-server.Listening()
+
+	go func() {
+		res, err := http.Get("http://" + e.ListenerAddr().String() + "/long")
+		if err == nil {
+			res.Body.Close()
+		}
+	}()
+
+	<-entered
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	deadline, _ := ctx.Deadline()
+
+	assert.NoError(t, server.Shutdown(ctx))
+
+	return deadline, <-cancelled
+}
+
+func TestShutdownCancelsRequestContexts(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithShutdownGrace(700*time.Millisecond))
+
+	deadline, at := shutdownCancellation(t, server, 2*time.Second)
+	assert.WithinDuration(t, deadline.Add(-700*time.Millisecond), at, 150*time.Millisecond)
+	assert.True(t, at.Before(deadline))
+}
+
+func TestShutdownGraceCapped(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	// the default grace is longer than half the timeout, so it is capped
+	// instead of cancelling the request right away
+	deadline, at := shutdownCancellation(t, server, 400*time.Millisecond)
+	assert.WithinDuration(t, deadline.Add(-200*time.Millisecond), at, 80*time.Millisecond)
+	assert.True(t, at.Before(deadline))
+}
+
+func TestServerTimeouts(t *testing.T) {
+	server, _ := NewServer()
+	assert.Zero(t, server.GetEcho().Server.ReadTimeout)
+	assert.Zero(t, server.GetEcho().Server.WriteTimeout)
+	assert.Zero(t, server.GetEcho().Server.IdleTimeout)
+
+	server, _ = NewServer(
+		WithHost("127.0.0.1"), WithPort("0"),
+		WithReadTimeout(100*time.Millisecond),
+		WithWriteTimeout(time.Second),
+		WithIdleTimeout(time.Minute),
+	)
+
+	e := server.GetEcho()
+	for _, hs := range []*http.Server{e.Server, e.TLSServer} {
+		assert.Equal(t, 100*time.Millisecond, hs.ReadTimeout)
+		assert.Equal(t, time.Second, hs.WriteTimeout)
+		assert.Equal(t, time.Minute, hs.IdleTimeout)
+	}
+
+	server.Start()
+	<-server.Listening()
+	defer server.Close()
+
+	// a slow client never finishing its headers is cut off by the read timeout
+	conn, err := net.Dial("tcp", e.ListenerAddr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	_, err = conn.Write([]byte("GET / HTTP/1.1\r\nHost: localhost\r\n"))
+	assert.NoError(t, err)
+
+	_ = conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	start := time.Now()
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), time.Second)
+}
+
+func TestRunWithSignals(t *testing.T) {
+	tests := []struct {
+		name string
+		stop func(cancel context.CancelFunc)
+	}{
+		{"Context cancelled", func(cancel context.CancelFunc) { cancel() }},
+		{"SIGTERM received", func(context.CancelFunc) {
+			_ = syscall.Kill(os.Getpid(), syscall.SIGTERM)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithShutdownTimeout(time.Second))
+
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+
+			done := make(chan error, 1)
+			go func() { done <- server.RunWithSignals(ctx) }()
+
+			// the signal handler is installed before the listener is bound
+			<-server.Listening()
+
+			tt.stop(cancel)
+
+			select {
+			case err := <-done:
+				assert.NoError(t, err)
+			case <-time.After(3 * time.Second):
+				t.Fatal("server did not shut down")
+			}
+			assert.True(t, server.draining.Load())
+		})
+	}
+}
+
+func TestRunWithCustomShutdownSignal(t *testing.T) {
+	server, _ := NewServer(
+		WithHost("127.0.0.1"), WithPort("0"),
+		WithShutdownTimeout(time.Second),
+		WithShutdownSignals(syscall.SIGUSR1),
+	)
+
+	done := make(chan error, 1)
+	go func() { done <- server.RunWithSignals(context.Background()) }()
+
+	<-server.Listening()
+	_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+
+	select {
+	case err := <-done:
+		assert.NoError(t, err)
+	case <-time.After(3 * time.Second):
+		t.Fatal("server did not shut down")
+	}
+	assert.True(t, server.draining.Load())
+}
+
+func TestRunWithSignalsBindError(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer l.Close()
+
+	_, port, _ := net.SplitHostPort(l.Addr().String())
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort(port))
+
+	assert.ErrorContains(t, server.RunWithSignals(context.Background()), "address already in use")
+}
+
+func TestDoubleStart(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	server.Start()
+	<-server.Listening()
+	addr := server.GetEcho().ListenerAddr().String()
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 4)
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			server.Start()
+			errs <- server.StartListening()
+		}()
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		assert.ErrorIs(t, err, ErrServerStarted)
+	}
+	assert.Equal(t, addr, server.GetEcho().ListenerAddr().String())
+
+	assert.NoError(t, server.GracefulShutdown())
+	assert.ErrorIs(t, server.StartListening(), ErrServerStopped)
+}
+
+func TestShutdownBeforeStart(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, server.GracefulShutdown())
+		}()
+	}
+	wg.Wait()
+
+	assert.NoError(t, server.Close())
+	assert.False(t, server.draining.Load())
+
+	// the no-op leaves the server startable
+	server.Start()
+	<-server.Listening()
+	assert.True(t, server.Started())
+
+	assert.NoError(t, server.GracefulShutdown())
+	assert.False(t, server.Started())
+	assert.ErrorIs(t, server.StartListening(), ErrServerStopped)
+}
+
+func TestRegisterRoutersWithPrefix(t *testing.T) {
+	server, _ := NewServer()
+
+	rr := NewRouters()
+	rr.AddRouter("/status", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	for _, prefix := range []string{"/internal", "/partner/"} {
+		assert.NoError(t, server.RegisterRoutersWithPrefix(prefix, rr, func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				c.Response().Header().Set("X-Scoped", "yes")
+				return next(c)
+			}
+		}))
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+	}{
+		{"Internal prefix", "/internal/status", http.StatusOK},
+		{"Trailing slash trimmed", "/partner/status", http.StatusOK},
+		{"Unprefixed path", "/status", http.StatusNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusOK {
+				assert.Equal(t, "yes", rec.Header().Get("X-Scoped"))
+			}
+		})
+	}
+
+	assert.Contains(t, server.ListRoutes(), RouteEntry{Group: ROOT, Method: http.MethodGet, Path: "/internal/status"})
+}
+
+func TestRegisterRoutersWithPrefixInvalid(t *testing.T) {
+	server, _ := NewServer(WithMaxRoutes(1))
+
+	rr := NewRouters()
+	rr.AddRouter("/status", Methods{
+		http.MethodGet:  func(c Context) error { return nil },
+		http.MethodPost: func(c Context) error { return nil },
+	})
+
+	assert.Error(t, server.RegisterRoutersWithPrefix("internal", rr))
+	assert.Error(t, server.RegisterRoutersWithPrefix("", rr))
+	assert.Error(t, server.RegisterRoutersWithPrefix("/internal", rr))
+	assert.Empty(t, server.GetRouters())
+}
+
+func TestReadHeaderTimeout(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithReadHeaderTimeout(200*time.Millisecond))
+
+	e := server.GetEcho()
+	assert.Equal(t, 200*time.Millisecond, e.Server.ReadHeaderTimeout)
+	assert.Zero(t, e.Server.ReadTimeout)
+
+	server.Start()
+	<-server.Listening()
+	defer server.Close()
+
+	conn, err := net.Dial("tcp", e.ListenerAddr().String())
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer conn.Close()
+
+	// trickle the request headers one byte at a time, never finishing them
+	start := time.Now()
+	headers := []byte("GET / HTTP/1.1\r\nHost: localhost\r\nX-Slow: " + strings.Repeat("a", 100))
+	var writeErr error
+	for _, b := range headers {
+		if _, writeErr = conn.Write([]byte{b}); writeErr != nil {
+			break
+		}
+		if time.Since(start) > 2*time.Second {
+			break
+		}
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, readErr := conn.Read(make([]byte, 1))
+
+	assert.True(t, writeErr != nil || readErr != nil, "connection was not dropped")
+	assert.Less(t, time.Since(start), 2*time.Second)
+}
+
+func TestRouterMiddlewares(t *testing.T) {
+	server, _ := NewServer()
+
+	admin := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			if c.Request().Header.Get("X-Admin") != "true" {
+				return echo.ErrForbidden
+			}
+			return next(c)
+		}
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/public", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "public")
+		},
+	})
+	assert.NoError(t, rr.AddRouterWithMiddleware("/admin", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "admin")
+		},
+	}, admin))
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	tests := []struct {
+		path   string
+		admin  bool
+		status int
+	}{
+		{"/v1/public", false, http.StatusOK},
+		{"/v1/admin", false, http.StatusForbidden},
+		{"/v1/admin", true, http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+		if tt.admin {
+			req.Header.Set("X-Admin", "true")
+		}
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		assert.Equal(t, tt.status, rec.Code, tt.path)
+	}
+}
+
+func TestRouterMethodMiddlewares(t *testing.T) {
+	server, _ := NewServer()
+
+	var order []string
+	trace := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				order = append(order, name)
+				return next(c)
+			}
+		}
+	}
+	limited := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return echo.ErrTooManyRequests
+		}
+	}
+
+	ok := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	rr := NewRouters()
+	assert.NoError(t, rr.AddRouterWithMethodMiddleware("/items", MethodsWithMiddleware{
+		http.MethodGet:  {Handler: ok, Middlewares: []MiddlewareFunc{trace("get")}},
+		http.MethodPost: {Handler: ok, Middlewares: []MiddlewareFunc{limited}},
+	}))
+	assert.NoError(t, rr.AddRoute(RegisterRouter{
+		Path:              "/orders",
+		Methods:           Methods{http.MethodGet: ok},
+		Middlewares:       []MiddlewareFunc{trace("router")},
+		MethodMiddlewares: map[string][]MiddlewareFunc{http.MethodGet: {trace("method")}},
+	}))
+	assert.Error(t, rr.AddRouterWithMethodMiddleware("/items", MethodsWithMiddleware{
+		http.MethodGet: {Handler: ok},
+	}))
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	serve := func(method, path string) int {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(method, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/v1/items"))
+	assert.Equal(t, http.StatusTooManyRequests, serve(http.MethodPost, "/v1/items"))
+	assert.Equal(t, http.StatusOK, serve(http.MethodGet, "/v1/orders"))
+	assert.Equal(t, []string{"get", "router", "method"}, order)
+}
+
+func TestPreStartBehavior(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithAdmin("/_admin", adminToken))
+
+	rr := NewRouters()
+	rr.AddRouter("/ready", Methods{http.MethodGet: server.ReadinessHandler()})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	get := func(path string) int {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set(echo.HeaderAuthorization, "Bearer admin")
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, req)
+		return rec.Code
+	}
+
+	// before Start there is no address, and readiness and admin endpoints
+	// are refused even though the server was marked ready
+	server.MarkReady()
+	assert.Empty(t, server.Addr())
+	assert.False(t, server.Started())
+	assert.False(t, server.IsReady())
+	assert.Equal(t, http.StatusServiceUnavailable, get("/ready"))
+	assert.Equal(t, http.StatusServiceUnavailable, get("/_admin/health"))
+
+	server.Start()
+	<-server.Listening()
+
+	assert.True(t, server.Started())
+	assert.Equal(t, server.GetEcho().ListenerAddr().String(), server.Addr())
+	assert.Eventually(t, server.IsReady, time.Second, 10*time.Millisecond)
+	assert.Equal(t, http.StatusOK, get("/ready"))
+	assert.Equal(t, http.StatusOK, get("/_admin/health"))
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	assert.NoError(t, server.Shutdown(ctx))
+
+	assert.Empty(t, server.Addr())
+	assert.False(t, server.Started())
+}
+
+func TestBodyLimit(t *testing.T) {
+	server, err := NewServer(WithBodyLimit("1K"))
+	assert.NoError(t, err)
+	assert.Contains(t, server.Middlewares(), "body-limit")
+
+	rr := NewRouters()
+	rr.AddRouter("/upload", Methods{
+		http.MethodPost: func(c Context) error {
+			type body struct {
+				Q string `json:"q"`
+			}
+
+			b := new(body)
+			if err := c.Bind(b); err != nil {
+				return err
+			}
+
+			return c.String(http.StatusOK, b.Q)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name     string
+		size     int
+		expected int
+	}{
+		{"Within limit", 100, http.StatusOK},
+		{"Over limit", 2048, http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := `{"q":"` + strings.Repeat("a", tt.size) + `"}`
+			req := httptest.NewRequest(http.MethodPost, "/upload", strings.NewReader(body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expected, rec.Code)
+		})
+	}
+}
+
+func TestRegisterOnShutdown(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	var mu sync.Mutex
+	var order []string
+	record := func(name string) {
+		mu.Lock()
+		defer mu.Unlock()
+		order = append(order, name)
+	}
+
+	started := make(chan struct{})
+	rr := NewRouters()
+	rr.AddRouter("/slow", Methods{
+		http.MethodGet: func(c Context) error {
+			close(started)
+			time.Sleep(200 * time.Millisecond)
+			record("request")
+			return c.NoContent(http.StatusOK)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	errPool := errors.New("pool close failed")
+	errFlush := errors.New("flush failed")
+	server.RegisterOnShutdown(func(ctx context.Context) error {
+		record("db")
+		return errPool
+	})
+	server.RegisterOnShutdown(func(ctx context.Context) error {
+		record("cache")
+		return nil
+	})
+	server.RegisterOnShutdown(func(ctx context.Context) error {
+		record("metrics")
+		return errFlush
+	})
+
+	server.Start()
+	<-server.Listening()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		resp, err := http.Get("http://" + server.Addr() + "/slow")
+		if assert.NoError(t, err) {
+			resp.Body.Close()
+		}
+	}()
+	<-started
+
+	err := server.GracefulShutdown()
+	<-done
+
+	assert.ErrorIs(t, err, errPool)
+	assert.ErrorIs(t, err, errFlush)
+	assert.Equal(t, []string{"request", "metrics", "cache", "db"}, order)
+
+	// hooks only run once
+	assert.NoError(t, server.GracefulShutdown())
+	assert.Len(t, order, 4)
+}
+
+func TestGroupSharedAcrossRegistrations(t *testing.T) {
+	calls := 0
+	counter := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			calls++
+			return next(c)
+		}
+	}
+	deny := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return echo.ErrForbidden
+		}
+	}
+
+	server, err := NewServer(WithGroupMiddleware(V1, counter))
+	assert.NoError(t, err)
+
+	ok := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	users := NewRouters()
+	users.AddRouter("/users", Methods{http.MethodGet: ok})
+	assert.NoError(t, server.RegisterRouters(V1, users))
+	group := server.groups[V1]
+
+	admin := NewRouters()
+	admin.AddRouter("/admin", Methods{http.MethodGet: ok})
+	assert.NoError(t, server.RegisterRouters(V1, admin, deny))
+	assert.Same(t, group, server.groups[V1])
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	// group middleware covers both registrations and runs once per request
+	assert.Equal(t, http.StatusOK, serve("/v1/users"))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, http.StatusForbidden, serve("/v1/admin"))
+	assert.Equal(t, 2, calls)
+
+	// call middlewares only wrap the routes of their own call
+	assert.Equal(t, http.StatusOK, serve("/v1/users"))
+}
+
+func TestRootCallMiddlewares(t *testing.T) {
+	deny := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			return echo.ErrForbidden
+		}
+	}
+
+	server, _ := NewServer()
+	ok := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	admin := NewRouters()
+	admin.AddRouter("/admin", Methods{http.MethodGet: ok})
+	assert.NoError(t, server.RegisterRouters(ROOT, admin, deny))
+
+	users := NewRouters()
+	users.AddRouter("/users", Methods{http.MethodGet: ok})
+	assert.NoError(t, server.RegisterRouters(ROOT, users))
+	assert.NoError(t, server.RegisterRouters(V1, users))
+
+	for path, code := range map[string]int{
+		"/admin":    http.StatusForbidden,
+		"/users":    http.StatusOK,
+		"/v1/users": http.StatusOK,
+	} {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		assert.Equal(t, code, rec.Code, path)
+	}
+}
+
+func TestGroupAccessor(t *testing.T) {
+	var seen []string
+	trace := func(name string) MiddlewareFunc {
+		return func(next HandlerFunc) HandlerFunc {
+			return func(c Context) error {
+				seen = append(seen, name)
+				return next(c)
+			}
+		}
+	}
+
+	server, _ := NewServer(WithGroupMiddleware(V1, trace("v1")))
+	ok := func(c Context) error { return c.NoContent(http.StatusOK) }
+
+	v1 := server.Group(V1)
+	assert.Same(t, v1, server.Group(V1))
+	assert.Nil(t, server.Group(Kind(99)))
+
+	admin := v1.Group("/admin", trace("admin"))
+	admin.GET("/stats", ok)
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{http.MethodGet: ok})
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	server.Group(ROOT).GET("/ping", ok)
+
+	serve := func(path string) int {
+		rec := httptest.NewRecorder()
+		server.GetEcho().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+		return rec.Code
+	}
+
+	assert.Equal(t, http.StatusOK, serve("/v1/admin/stats"))
+	assert.Equal(t, []string{"v1", "admin"}, seen)
+
+	seen = nil
+	assert.Equal(t, http.StatusOK, serve("/v1/users"))
+	assert.Equal(t, []string{"v1"}, seen)
+
+	assert.Equal(t, http.StatusOK, serve("/ping"))
+}
+
+func TestGracefulShutdownPreShutdownDelay(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithPreShutdownDelay(500*time.Millisecond))
+	assert.NoError(t, server.RegisterHealthChecks(HealthOptions{}))
+
+	rr := NewRouters()
+	rr.AddRouter("/ping", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "pong")
+		},
+	})
+	rr.AddRouter("/ready", Methods{http.MethodGet: server.ReadinessHandler()})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	server.Start()
+	<-server.Listening()
+	addr := "http://" + server.Addr()
+
+	server.MarkReady()
+	assert.Eventually(t, server.IsReady, time.Second, 10*time.Millisecond)
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- server.GracefulShutdown() }()
+
+	assert.Eventually(t, server.draining.Load, time.Second, 10*time.Millisecond)
+	assert.False(t, server.IsReady())
+
+	for _, path := range []string{"/readyz", "/ready"} {
+		res, err := http.Get(addr + path)
+		if assert.NoError(t, err) {
+			res.Body.Close()
+			assert.Equal(t, http.StatusServiceUnavailable, res.StatusCode, path)
+		}
+	}
+
+	res, err := http.Get(addr + "/ping")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "pong", string(body))
+	}
+
+	select {
+	case err := <-shutdown:
+		t.Fatalf("shutdown returned during the delay: %v", err)
+	default:
+	}
+
+	assert.NoError(t, <-shutdown)
+	assert.False(t, server.Started())
+}
+
+func TestGracefulShutdownPreShutdownDelayClose(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithPreShutdownDelay(time.Minute))
+
+	server.Start()
+	<-server.Listening()
+
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- server.GracefulShutdown() }()
+
+	assert.Eventually(t, server.draining.Load, time.Second, 10*time.Millisecond)
+	assert.NoError(t, server.Close())
+
+	select {
+	case <-shutdown:
+	case <-time.After(3 * time.Second):
+		t.Fatal("Close did not cut the pre-shutdown delay short")
+	}
+}
@@ -0,0 +1,29 @@
+package server
+
+import (
+	"strings"
+
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// spa serves the single page app in dir: existing files are served as is
+// and other paths fall back to index.html so client-side routing works.
+// Paths under an API prefix are left to the router, so unknown API routes
+// still answer a JSON 404.
+func spa(dir string, apiPrefixes []string) MiddlewareFunc {
+	return middleware.StaticWithConfig(middleware.StaticConfig{
+		Root:  dir,
+		Index: "index.html",
+		HTML5: true,
+		Skipper: func(c Context) bool {
+			path := c.Request().URL.Path
+			for _, prefix := range apiPrefixes {
+				prefix = strings.TrimSuffix(prefix, "/")
+				if path == prefix || strings.HasPrefix(path, prefix+"/") {
+					return true
+				}
+			}
+			return false
+		},
+	})
+}
@@ -0,0 +1,62 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithSPA(t *testing.T) {
+	dir := t.TempDir()
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "index.html"), []byte("<html>app</html>"), 0o600))
+	assert.NoError(t, os.MkdirAll(filepath.Join(dir, "assets"), 0o700))
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, "assets", "app.js"), []byte("console.log('app')"), 0o600))
+
+	server, err := NewServer(WithSPA(dir, []string{"/api", "/v1"}))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.JSON(http.StatusOK, []string{"ada"})
+		},
+	})
+	_ = server.RegisterRouters(API, rr)
+
+	tests := []struct {
+		name         string
+		path         string
+		expectedCode int
+		expectedBody string
+		expectedType string
+	}{
+		{"Index", "/", http.StatusOK, "<html>app</html>", "text/html"},
+		{"Static file", "/assets/app.js", http.StatusOK, "console.log('app')", "javascript"},
+		{"UI route falls back to index", "/settings/profile", http.StatusOK, "<html>app</html>", "text/html"},
+		{"API route", "/api/users", http.StatusOK, `["ada"]` + "\n", "application/json"},
+		{"Unknown API route", "/api/orders", http.StatusNotFound, `{"message":"Not Found"}` + "\n", "application/json"},
+		{"Unknown API group route", "/v1/orders", http.StatusNotFound, `{"message":"Not Found"}` + "\n", "application/json"},
+		{"Traversal stays in dir", "/../../etc/passwd", http.StatusOK, "<html>app</html>", "text/html"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.path, nil)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+			assert.Contains(t, rec.Header().Get("Content-Type"), tt.expectedType)
+		})
+	}
+}
+
+func TestWithSPAMissingIndex(t *testing.T) {
+	_, err := NewServer(WithSPA(t.TempDir(), nil))
+	assert.Error(t, err)
+}
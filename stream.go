@@ -0,0 +1,51 @@
+package server
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// MIMEApplicationNDJSON is the content type of newline-delimited JSON
+const MIMEApplicationNDJSON = "application/x-ndjson"
+
+// StreamNDJSON writes each item received from items as one line of JSON,
+// flushing after every line so clients see events as they happen. It
+// returns when items is closed or the client disconnects.
+func StreamNDJSON(c Context, items <-chan any) error {
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, MIMEApplicationNDJSON)
+	res.WriteHeader(http.StatusOK)
+	res.Flush()
+
+	enc := json.NewEncoder(res)
+	done := c.Request().Context().Done()
+
+	for {
+		select {
+		case <-done:
+			return c.Request().Context().Err()
+		case item, ok := <-items:
+			if !ok {
+				return nil
+			}
+			if err := enc.Encode(item); err != nil {
+				return err
+			}
+			res.Flush()
+		}
+	}
+}
+
+// StreamContent serves content with http.ServeContent, so in-memory or
+// remote content gets the Range, If-Modified-Since and If-None-Match
+// handling of files. The content type is taken from the Content-Type header
+// when set, else from the extension of name or the first bytes of content.
+// A zero modtime disables Last-Modified.
+func StreamContent(c Context, name string, modtime time.Time, content io.ReadSeeker) error {
+	http.ServeContent(c.Response(), c.Request(), name, modtime, content)
+	return nil
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStreamNDJSON(t *testing.T) {
+	c := TestContext(http.MethodGet, "/events")
+
+	items := make(chan any, 3)
+	items <- map[string]int{"id": 1}
+	items <- map[string]int{"id": 2}
+	items <- map[string]int{"id": 3}
+	close(items)
+
+	assert.NoError(t, StreamNDJSON(c, items))
+
+	rec := c.Response().Writer.(*httptest.ResponseRecorder)
+	assert.Equal(t, MIMEApplicationNDJSON, rec.Header().Get("Content-Type"))
+	assert.True(t, rec.Flushed)
+
+	lines := strings.Split(strings.TrimSuffix(rec.Body.String(), "\n"), "\n")
+	assert.Len(t, lines, 3)
+	for i, line := range lines {
+		var item map[string]int
+		assert.NoError(t, json.Unmarshal([]byte(line), &item))
+		assert.Equal(t, i+1, item["id"])
+	}
+}
+
+func TestStreamNDJSONClientDisconnect(t *testing.T) {
+	c := TestContext(http.MethodGet, "/events")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c.SetRequest(c.Request().WithContext(ctx))
+	cancel()
+
+	// items is never closed, so only the disconnect can end the stream
+	err := StreamNDJSON(c, make(chan any))
+	assert.ErrorIs(t, err, context.Canceled)
+}
+
+func TestStreamContent(t *testing.T) {
+	content := []byte("0123456789abcdefghij")
+	modtime := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/report.txt", Methods{
+		http.MethodGet: func(c Context) error {
+			return StreamContent(c, "report.txt", modtime, bytes.NewReader(content))
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name         string
+		header       string
+		value        string
+		expectedCode int
+		expectedBody string
+	}{
+		{"full", "", "", http.StatusOK, string(content)},
+		{"range", "Range", "bytes=5-9", http.StatusPartialContent, "56789"},
+		{"suffix range", "Range", "bytes=-3", http.StatusPartialContent, "hij"},
+		{"unsatisfiable", "Range", "bytes=50-60", http.StatusRequestedRangeNotSatisfiable, ""},
+		{"not modified", "If-Modified-Since", modtime.Format(http.TimeFormat), http.StatusNotModified, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/report.txt", nil)
+			if len(tt.header) > 0 {
+				req.Header.Set(tt.header, tt.value)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if tt.expectedCode == http.StatusRequestedRangeNotSatisfiable {
+				return
+			}
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+			assert.Equal(t, modtime.Format(http.TimeFormat), rec.Header().Get("Last-Modified"))
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/report.txt", nil)
+	req.Header.Set("Range", "bytes=5-9")
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+	assert.Equal(t, "bytes 5-9/20", rec.Header().Get("Content-Range"))
+	assert.Equal(t, "text/plain; charset=utf-8", rec.Header().Get("Content-Type"))
+}
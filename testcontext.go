@@ -0,0 +1,58 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// TestContextOption configures a context built by TestContext
+type TestContextOption func(tc *testContext)
+
+type testContext struct {
+	req         *http.Request
+	paramNames  []string
+	paramValues []string
+}
+
+// WithTestBody sets the request body
+func WithTestBody(body string) TestContextOption {
+	return func(tc *testContext) {
+		tc.req.Body = io.NopCloser(strings.NewReader(body))
+		tc.req.ContentLength = int64(len(body))
+	}
+}
+
+// WithTestHeader sets a request header
+func WithTestHeader(key, value string) TestContextOption {
+	return func(tc *testContext) {
+		tc.req.Header.Set(key, value)
+	}
+}
+
+// WithTestParam sets a path parameter as if the route had matched it
+func WithTestParam(name, value string) TestContextOption {
+	return func(tc *testContext) {
+		tc.paramNames = append(tc.paramNames, name)
+		tc.paramValues = append(tc.paramValues, value)
+	}
+}
+
+// TestContext builds an echo context for unit-testing a handler in
+// isolation, without registering it or going through routing. The response
+// writer is a *httptest.ResponseRecorder.
+func TestContext(method, path string, opts ...TestContextOption) Context {
+	tc := &testContext{req: httptest.NewRequest(method, path, nil)}
+	for _, opt := range opts {
+		opt(tc)
+	}
+
+	c := echo.New().NewContext(tc.req, httptest.NewRecorder())
+	c.SetParamNames(tc.paramNames...)
+	c.SetParamValues(tc.paramValues...)
+
+	return c
+}
@@ -0,0 +1,34 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTestContext(t *testing.T) {
+	handler := func(c Context) error {
+		var body struct {
+			Name string `json:"name"`
+		}
+		if err := c.Bind(&body); err != nil {
+			return err
+		}
+		return c.String(http.StatusOK, c.Param("id")+":"+c.Param("part")+":"+body.Name)
+	}
+
+	c := TestContext(http.MethodPost, "/items/42/a",
+		WithTestHeader("Content-Type", "application/json"),
+		WithTestBody(`{"name":"widget"}`),
+		WithTestParam("id", "42"),
+		WithTestParam("part", "a"),
+	)
+
+	assert.NoError(t, handler(c))
+
+	rec := c.Response().Writer.(*httptest.ResponseRecorder)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "42:a:widget", rec.Body.String())
+}
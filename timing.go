@@ -0,0 +1,54 @@
+package server
+
+import (
+	"strconv"
+	"strings"
+	"time"
+)
+
+const timingsKey = "echowr.timings"
+
+// Timing is a named sub-timing of a request
+type Timing struct {
+	Name     string
+	Duration time.Duration
+}
+
+// AddTiming accumulates a named sub-timing for the request (e.g. "db",
+// "cache", "render"). Timings are sent in the Server-Timing header when the
+// response is written, so they must be added before that.
+func AddTiming(c Context, name string, d time.Duration) {
+	timings, ok := c.Get(timingsKey).([]Timing)
+	if !ok {
+		c.Response().Before(func() {
+			if header := serverTiming(Timings(c)); len(header) > 0 {
+				c.Response().Header().Set("Server-Timing", header)
+			}
+		})
+	}
+
+	for i := range timings {
+		if timings[i].Name == name {
+			timings[i].Duration += d
+			return
+		}
+	}
+
+	c.Set(timingsKey, append(timings, Timing{Name: name, Duration: d}))
+}
+
+// Timings returns the sub-timings added to the request, in insertion order
+func Timings(c Context) []Timing {
+	timings, _ := c.Get(timingsKey).([]Timing)
+	return timings
+}
+
+// serverTiming formats timings as a Server-Timing header value
+func serverTiming(timings []Timing) string {
+	parts := make([]string, 0, len(timings))
+	for _, t := range timings {
+		ms := float64(t.Duration) / float64(time.Millisecond)
+		parts = append(parts, t.Name+";dur="+strconv.FormatFloat(ms, 'f', -1, 64))
+	}
+	return strings.Join(parts, ", ")
+}
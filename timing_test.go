@@ -0,0 +1,48 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAddTiming(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			AddTiming(c, "db", 12*time.Millisecond)
+			AddTiming(c, "cache", 1500*time.Microsecond)
+			AddTiming(c, "db", 3*time.Millisecond)
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "db;dur=15, cache;dur=1.5", rec.Header().Get("Server-Timing"))
+}
+
+func TestTimings(t *testing.T) {
+	server, _ := NewServer()
+
+	req := httptest.NewRequest(http.MethodGet, "/test", nil)
+	rec := httptest.NewRecorder()
+	c := server.NewContext(req, rec)
+
+	assert.Empty(t, Timings(c))
+
+	AddTiming(c, "render", time.Millisecond)
+	assert.Equal(t, []Timing{{Name: "render", Duration: time.Millisecond}}, Timings(c))
+
+	assert.NoError(t, c.NoContent(http.StatusNoContent))
+	assert.Equal(t, "render;dur=1", rec.Header().Get("Server-Timing"))
+}
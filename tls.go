@@ -0,0 +1,59 @@
+package server
+
+import (
+	"crypto/tls"
+
+	"golang.org/x/crypto/acme"
+	"golang.org/x/crypto/acme/autocert"
+)
+
+// tlsConfig returns the TLS constraints set with WithTLSConfig, or nil when
+// none are. Cipher suites only restrict TLS 1.2 and earlier; TLS 1.3 suites
+// are not configurable.
+func (s *ServerParams) tlsConfig() *tls.Config {
+	if s.GetTLSMinVersion() == 0 {
+		return nil
+	}
+
+	return &tls.Config{
+		MinVersion:   s.GetTLSMinVersion(),
+		CipherSuites: s.GetTLSCipherSuites(),
+	}
+}
+
+// useTLS reports whether the server serves HTTPS
+func (s *ServerParams) useTLS() bool {
+	return len(s.GetTLSCertFile()) > 0 || len(s.GetAutoTLSDomains()) > 0
+}
+
+// serverTLSConfig builds the TLS config of the HTTPS listener, honouring
+// WithTLSConfig, or returns nil when the server serves plain HTTP
+func (s *Server) serverTLSConfig() (*tls.Config, error) {
+	if !s.params.useTLS() {
+		return nil, nil
+	}
+
+	config := s.params.tlsConfig()
+	if config == nil {
+		config = &tls.Config{}
+	}
+
+	if certFile := s.params.GetTLSCertFile(); len(certFile) > 0 {
+		cert, err := tls.LoadX509KeyPair(certFile, s.params.GetTLSKeyFile())
+		if err != nil {
+			return nil, err
+		}
+		config.Certificates = []tls.Certificate{cert}
+	} else {
+		s.front.AutoTLSManager.HostPolicy = autocert.HostWhitelist(s.params.GetAutoTLSDomains()...)
+		config.GetCertificate = s.front.AutoTLSManager.GetCertificate
+		config.NextProtos = append(config.NextProtos, acme.ALPNProto)
+	}
+
+	if !s.front.DisableHTTP2 {
+		config.NextProtos = append(config.NextProtos, "h2")
+	}
+	config.NextProtos = append(config.NextProtos, "http/1.1")
+
+	return config, nil
+}
@@ -0,0 +1,234 @@
+package server
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"io"
+	"math/big"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// writeTestCert writes a self-signed certificate for 127.0.0.1 and its key
+// to dir
+func writeTestCert(t *testing.T, dir string) (string, string) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "127.0.0.1"},
+		IPAddresses:  []net.IP{net.ParseIP("127.0.0.1")},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certFile := filepath.Join(dir, "cert.pem")
+	keyFile := filepath.Join(dir, "key.pem")
+	if err := os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	return certFile, keyFile
+}
+
+func TestWithTLSConfig(t *testing.T) {
+	allowed := tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256
+	server, err := NewServer(WithTLSConfig(tls.VersionTLS12, []uint16{allowed}))
+	assert.NoError(t, err)
+
+	ts := httptest.NewUnstartedServer(server.GetEcho())
+	ts.TLS = server.GetEcho().TLSServer.TLSConfig
+	ts.StartTLS()
+	defer ts.Close()
+
+	addr := strings.TrimPrefix(ts.URL, "https://")
+
+	tests := []struct {
+		name    string
+		config  *tls.Config
+		wantErr bool
+	}{
+		{
+			name:    "TLS 1.0 client is rejected",
+			config:  &tls.Config{MinVersion: tls.VersionTLS10, MaxVersion: tls.VersionTLS10},
+			wantErr: true,
+		},
+		{
+			name: "Disallowed cipher is rejected",
+			config: &tls.Config{
+				MaxVersion:   tls.VersionTLS12,
+				CipherSuites: []uint16{tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384},
+			},
+			wantErr: true,
+		},
+		{
+			name: "Allowed cipher is accepted",
+			config: &tls.Config{
+				MaxVersion:   tls.VersionTLS12,
+				CipherSuites: []uint16{allowed},
+			},
+		},
+		{
+			name:   "TLS 1.3 client is accepted",
+			config: &tls.Config{MinVersion: tls.VersionTLS13},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.config.InsecureSkipVerify = true
+			conn, err := tls.Dial("tcp", addr, tt.config)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			conn.Close()
+		})
+	}
+}
+
+func TestWithTLSConfigInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		min     uint16
+		ciphers []uint16
+	}{
+		{"Unknown version", 0x0200, nil},
+		{"Insecure cipher", tls.VersionTLS12, []uint16{tls.TLS_RSA_WITH_RC4_128_SHA}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServer(WithTLSConfig(tt.min, tt.ciphers))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestTLSConfigUnset(t *testing.T) {
+	server, _ := NewServer()
+	assert.Nil(t, server.GetEcho().TLSServer.TLSConfig)
+}
+
+func TestWithTLS(t *testing.T) {
+	certFile, keyFile := writeTestCert(t, t.TempDir())
+
+	server, err := NewServer(
+		WithHost("127.0.0.1"), WithPort("0"),
+		WithTLS(certFile, keyFile),
+		WithTLSConfig(tls.VersionTLS12, nil),
+	)
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/secure", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, c.Request().Proto)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	done := make(chan error, 1)
+	go func() { done <- server.StartListening() }()
+	<-server.Listening()
+
+	addr := server.GetEcho().TLSListenerAddr().String()
+	client := &http.Client{Transport: &http.Transport{
+		TLSClientConfig:   &tls.Config{InsecureSkipVerify: true},
+		ForceAttemptHTTP2: true,
+	}}
+
+	res, err := client.Get("https://" + addr + "/secure")
+	if assert.NoError(t, err) {
+		body, _ := io.ReadAll(res.Body)
+		res.Body.Close()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "HTTP/2.0", string(body))
+	}
+
+	_, err = tls.Dial("tcp", addr, &tls.Config{InsecureSkipVerify: true, MaxVersion: tls.VersionTLS11})
+	assert.Error(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+	defer cancel()
+	assert.NoError(t, server.Shutdown(ctx))
+	assert.NoError(t, <-done)
+}
+
+func TestWithTLSInvalid(t *testing.T) {
+	dir := t.TempDir()
+	certFile, keyFile := writeTestCert(t, dir)
+
+	tests := []struct {
+		name string
+		opts []Options
+	}{
+		{"Missing key", []Options{WithTLS(certFile, "")}},
+		{"Missing cert", []Options{WithTLS("", keyFile)}},
+		{"Nonexistent cert", []Options{WithTLS(filepath.Join(dir, "missing.pem"), keyFile)}},
+		{"Auto TLS without domains", []Options{WithAutoTLS()}},
+		{"Both TLS modes", []Options{WithTLS(certFile, keyFile), WithAutoTLS("example.com")}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewServer(tt.opts...)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestWithAutoTLS(t *testing.T) {
+	server, err := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithAutoTLS("example.com"))
+	if !assert.NoError(t, err) {
+		return
+	}
+
+	if !assert.NoError(t, server.listen()) {
+		return
+	}
+	defer server.GetEcho().TLSListener.Close()
+
+	config := server.GetEcho().TLSServer.TLSConfig
+	if assert.NotNil(t, config) {
+		assert.NotNil(t, config.GetCertificate)
+		assert.Contains(t, config.NextProtos, "acme-tls/1")
+	}
+
+	policy := server.GetEcho().AutoTLSManager.HostPolicy
+	assert.NoError(t, policy(context.Background(), "example.com"))
+	assert.Error(t, policy(context.Background(), "evil.example"))
+}
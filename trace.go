@@ -0,0 +1,74 @@
+package server
+
+import (
+	"encoding/hex"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// HeaderTraceParent is the W3C Trace Context header
+const HeaderTraceParent = "traceparent"
+
+// TraceID returns the trace ID of the request from its W3C traceparent
+// header, or an empty string when the request is not traced
+func TraceID(c Context) string {
+	parts := strings.Split(c.Request().Header.Get(HeaderTraceParent), "-")
+	if len(parts) != 4 || len(parts[1]) != 32 {
+		return ""
+	}
+
+	id, err := hex.DecodeString(parts[1])
+	if err != nil {
+		return ""
+	}
+	for _, b := range id {
+		if b != 0 {
+			return parts[1]
+		}
+	}
+	return ""
+}
+
+// observeLatency records the request latency in seconds, attaching the
+// trace ID as an exemplar so a metric spike links to an example trace
+func observeLatency(h *prometheus.HistogramVec, c Context, err error, d time.Duration) {
+	route := c.Path()
+	if err == echo.ErrNotFound {
+		route = ""
+	}
+
+	obs := h.With(prometheus.Labels{
+		"method": c.Request().Method,
+		"route":  route,
+		"status": strconv.Itoa(c.Response().Status),
+	})
+
+	if id := TraceID(c); len(id) > 0 {
+		if eo, ok := obs.(prometheus.ExemplarObserver); ok {
+			eo.ObserveWithExemplar(d.Seconds(), prometheus.Labels{"trace_id": id})
+			return
+		}
+	}
+	obs.Observe(d.Seconds())
+}
+
+// latencyHistogram observes request latencies into h
+func latencyHistogram(h *prometheus.HistogramVec) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			start := time.Now()
+
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			observeLatency(h, c, err, time.Since(start))
+			return err
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gookit/slog"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+)
+
+const testTraceParent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestTraceID(t *testing.T) {
+	tests := []struct {
+		name     string
+		header   string
+		expected string
+	}{
+		{"Valid", testTraceParent, "4bf92f3577b34da6a3ce929d0e0e4736"},
+		{"Missing", "", ""},
+		{"Malformed", "00-abc-00f067aa0ba902b7-01", ""},
+		{"Not hex", "00-zzf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", ""},
+		{"All zeros", "00-00000000000000000000000000000000-00f067aa0ba902b7-01", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := TestContext(http.MethodGet, "/", WithTestHeader(HeaderTraceParent, tt.header))
+			assert.Equal(t, tt.expected, TraceID(c))
+		})
+	}
+}
+
+func TestLatencyHistogramExemplar(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.NewJSONSugared(&buf, slog.DebugLevel)
+
+	registry := prometheus.NewRegistry()
+	histogram := prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name: "http_request_duration_seconds",
+		Help: "Request latency.",
+	}, []string{"method", "route", "status"})
+	registry.MustRegister(histogram)
+
+	server, err := NewServer(WithSlog(logger), WithAccessLog(), WithLatencyHistogram(histogram))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	rr.AddRouter("/users/:id", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	req := httptest.NewRequest(http.MethodGet, "/users/42", nil)
+	req.Header.Set(HeaderTraceParent, testTraceParent)
+	server.GetEcho().ServeHTTP(httptest.NewRecorder(), req)
+
+	families, err := registry.Gather()
+	if !assert.NoError(t, err) || !assert.Len(t, families, 1) {
+		return
+	}
+
+	metrics := families[0].GetMetric()
+	if !assert.Len(t, metrics, 1) {
+		return
+	}
+
+	labels := map[string]string{}
+	for _, l := range metrics[0].GetLabel() {
+		labels[l.GetName()] = l.GetValue()
+	}
+	assert.Equal(t, map[string]string{"method": "GET", "route": "/users/:id", "status": "200"}, labels)
+
+	var exemplars []string
+	for _, b := range metrics[0].GetHistogram().GetBucket() {
+		if ex := b.GetExemplar(); ex != nil {
+			for _, l := range ex.GetLabel() {
+				exemplars = append(exemplars, l.GetName()+"="+l.GetValue())
+			}
+		}
+	}
+	assert.Equal(t, []string{"trace_id=4bf92f3577b34da6a3ce929d0e0e4736"}, exemplars)
+
+	assert.NoError(t, logger.Flush())
+	records := accessLogRecords(t, &buf)
+	if assert.Len(t, records, 1) {
+		assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", records[0]["trace_id"])
+	}
+}
+
+func TestWithLatencyHistogramInvalid(t *testing.T) {
+	_, err := NewServer(WithLatencyHistogram(nil))
+	assert.Error(t, err)
+
+	wrongLabels := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "latency"}, []string{"path"})
+	_, err = NewServer(WithLatencyHistogram(wrongLabels))
+	assert.Error(t, err)
+}
@@ -0,0 +1,47 @@
+package server
+
+import (
+	"net/http"
+	"net/textproto"
+	"strings"
+)
+
+// DeclareTrailers announces in the Trailer header the trailers the handler
+// will set once the body is written. It must be called before the response
+// is committed; later calls are ignored.
+func DeclareTrailers(c Context, keys ...string) {
+	res := c.Response()
+	if res.Committed {
+		return
+	}
+	for _, key := range keys {
+		res.Header().Add("Trailer", textproto.CanonicalMIMEHeaderKey(key))
+	}
+}
+
+// SetTrailer sets a trailer sent after the response body, such as a status
+// computed while streaming. Calling it before the body is written declares
+// the trailer too; trailers set after the response is committed without
+// being declared are still sent but clients reading headers won't expect
+// them.
+func SetTrailer(c Context, key, value string) {
+	key = textproto.CanonicalMIMEHeaderKey(key)
+
+	res := c.Response()
+	if !res.Committed && !declaredTrailer(res.Header(), key) {
+		res.Header().Add("Trailer", key)
+	}
+	res.Header().Set(http.TrailerPrefix+key, value)
+}
+
+// declaredTrailer reports whether key is listed in the Trailer header
+func declaredTrailer(h http.Header, key string) bool {
+	for _, v := range h.Values("Trailer") {
+		for _, k := range strings.Split(v, ",") {
+			if textproto.CanonicalMIMEHeaderKey(strings.TrimSpace(k)) == key {
+				return true
+			}
+		}
+	}
+	return false
+}
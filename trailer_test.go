@@ -0,0 +1,56 @@
+package server
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTrailer(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/stream", Methods{
+		http.MethodGet: func(c Context) error {
+			DeclareTrailers(c, "grpc-status")
+			SetTrailer(c, "X-Checksum", "early")
+
+			c.Response().WriteHeader(http.StatusOK)
+			_, _ = c.Response().Write([]byte("chunk 1\n"))
+			c.Response().Flush()
+			_, _ = c.Response().Write([]byte("chunk 2\n"))
+
+			SetTrailer(c, "Grpc-Status", "0")
+			SetTrailer(c, "X-Checksum", "abc")
+			return nil
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	ts := httptest.NewUnstartedServer(server.GetEcho())
+	ts.EnableHTTP2 = true
+	ts.StartTLS()
+	defer ts.Close()
+
+	resp, err := ts.Client().Get(ts.URL + "/stream")
+	if !assert.NoError(t, err) {
+		return
+	}
+	defer resp.Body.Close()
+
+	assert.Equal(t, 2, resp.ProtoMajor)
+	assert.Empty(t, resp.Header.Get("Grpc-Status"))
+	assert.Empty(t, resp.Header.Get("X-Checksum"))
+
+	// trailers are only filled in once the body is consumed
+	assert.Contains(t, resp.Trailer, "Grpc-Status")
+	assert.Empty(t, resp.Trailer.Get("Grpc-Status"))
+
+	body, err := io.ReadAll(resp.Body)
+	assert.NoError(t, err)
+	assert.Equal(t, "chunk 1\nchunk 2\n", string(body))
+	assert.Equal(t, "0", resp.Trailer.Get("Grpc-Status"))
+	assert.Equal(t, "abc", resp.Trailer.Get("X-Checksum"))
+}
@@ -0,0 +1,135 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// PayloadTransformFunc rewrites a request or response body
+type PayloadTransformFunc func([]byte) ([]byte, error)
+
+// PayloadTransform upgrades request bodies and downgrades response bodies
+// for the routes of a group, so an older API version can share handlers
+// with a newer one
+type PayloadTransform struct {
+	Group Kind
+	Up    PayloadTransformFunc
+	Down  PayloadTransformFunc
+}
+
+// groupTransforms returns the payload transform middlewares scoped to the
+// given group
+func (s *ServerParams) groupTransforms(group Kind) []MiddlewareFunc {
+	var mws []MiddlewareFunc
+	for _, t := range s.PayloadTransforms {
+		if t.Group == group {
+			mws = append(mws, transformPayload(t.Up, t.Down))
+		}
+	}
+	return mws
+}
+
+// transformWriter holds back the response so it can be transformed once the
+// handler is done
+type transformWriter struct {
+	http.ResponseWriter
+	status int
+	buf    bytes.Buffer
+}
+
+func (w *transformWriter) WriteHeader(code int) {
+	w.status = code
+}
+
+func (w *transformWriter) Write(b []byte) (int, error) {
+	return w.buf.Write(b)
+}
+
+// Flush is a no-op: nothing reaches the client before the transform runs
+func (w *transformWriter) Flush() {}
+
+// transformPayload applies up to non-empty request bodies, answering 400 if
+// it fails, and down to the response body, answering 500 if it fails
+func transformPayload(up, down PayloadTransformFunc) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			req := c.Request()
+			if up != nil && req.Body != nil {
+				body, err := io.ReadAll(req.Body)
+				if err != nil {
+					return err
+				}
+				if len(body) > 0 {
+					if body, err = up(body); err != nil {
+						return echo.NewHTTPError(http.StatusBadRequest, "invalid request payload")
+					}
+				}
+				req.Body = io.NopCloser(bytes.NewReader(body))
+				req.ContentLength = int64(len(body))
+			}
+
+			if down == nil {
+				return next(c)
+			}
+
+			return transformResponse(c, next, func(status int, body []byte) (int, []byte) {
+				body, err := down(body)
+				if err != nil {
+					c.Response().Header().Set(echo.HeaderContentType, echo.MIMETextPlainCharsetUTF8)
+					return http.StatusInternalServerError, []byte(http.StatusText(http.StatusInternalServerError))
+				}
+				return status, body
+			})
+		}
+	}
+}
+
+// transformResponse runs next with the response held back, then writes the
+// status and body returned by fn. Nothing is written if next didn't respond.
+func transformResponse(c Context, next HandlerFunc, fn func(status int, body []byte) (int, []byte)) error {
+	res := c.Response()
+	w := &transformWriter{ResponseWriter: res.Writer, status: http.StatusOK}
+	res.Writer = w
+
+	err := next(c)
+	res.Writer = w.ResponseWriter
+
+	if !res.Committed {
+		return err
+	}
+
+	status, body := fn(w.status, w.buf.Bytes())
+
+	// outer middlewares such as the access log read the final response
+	res.Status = status
+	res.Size = int64(len(body))
+
+	res.Header().Del(echo.HeaderContentLength)
+	w.ResponseWriter.WriteHeader(status)
+	_, _ = w.ResponseWriter.Write(body)
+
+	return err
+}
+
+// rewriteResponses passes every response body, error responses included,
+// through fn before it is written
+func rewriteResponses(fn func(status int, body []byte) []byte) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		handle := func(c Context) error {
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+			return err
+		}
+
+		return func(c Context) error {
+			return transformResponse(c, handle, func(status int, body []byte) (int, []byte) {
+				return status, fn(status, body)
+			})
+		}
+	}
+}
@@ -0,0 +1,185 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type userV2 struct {
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// upgradeUser turns a V1 {"name": "First Last"} body into the V2 shape
+func upgradeUser(body []byte) ([]byte, error) {
+	var v1 struct {
+		Name string `json:"name"`
+	}
+	if err := json.Unmarshal(body, &v1); err != nil {
+		return nil, err
+	}
+	first, last, _ := strings.Cut(v1.Name, " ")
+	return json.Marshal(userV2{FirstName: first, LastName: last})
+}
+
+// downgradeUser turns a V2 body back into the V1 shape
+func downgradeUser(body []byte) ([]byte, error) {
+	var v2 userV2
+	if err := json.Unmarshal(body, &v2); err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]string{"name": v2.FirstName + " " + v2.LastName})
+}
+
+func TestWithPayloadTransform(t *testing.T) {
+	server, err := NewServer(WithPayloadTransform(V1, upgradeUser, downgradeUser))
+	assert.NoError(t, err)
+
+	handler := func(c Context) error {
+		var user userV2
+		if err := c.Bind(&user); err != nil {
+			return err
+		}
+		return c.JSON(http.StatusCreated, user)
+	}
+
+	for _, group := range []Kind{V1, V2} {
+		rr := NewRouters()
+		rr.AddRouter("/users", Methods{http.MethodPost: handler})
+		assert.NoError(t, server.RegisterRouters(group, rr))
+	}
+
+	tests := []struct {
+		name         string
+		path         string
+		body         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"V1 is upgraded and downgraded", "/v1/users", `{"name":"Ada Lovelace"}`, http.StatusCreated, `{"name":"Ada Lovelace"}`},
+		{"V2 is untouched", "/v2/users", `{"first_name":"Ada","last_name":"Lovelace"}`, http.StatusCreated, `{"first_name":"Ada","last_name":"Lovelace"}`},
+		{"Invalid V1 body", "/v1/users", `not json`, http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, tt.path, strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if len(tt.expectedBody) > 0 {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			}
+		})
+	}
+}
+
+func TestWithPayloadTransformDownFailure(t *testing.T) {
+	down := func([]byte) ([]byte, error) { return nil, errors.New("cannot downgrade") }
+	server, _ := NewServer(WithPayloadTransform(V1, nil, down))
+
+	var status int
+	server.Use(func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			err := next(c)
+			status = c.Response().Status
+			return err
+		}
+	})
+
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.JSON(http.StatusOK, userV2{FirstName: "Ada"})
+		},
+	})
+	assert.NoError(t, server.RegisterRouters(V1, rr))
+
+	req := httptest.NewRequest(http.MethodGet, "/v1/users", nil)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, http.StatusInternalServerError, status)
+	assert.NotContains(t, rec.Body.String(), "Ada")
+}
+
+func TestWithPayloadTransformInvalid(t *testing.T) {
+	_, err := NewServer(WithPayloadTransform(V1, nil, nil))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithPayloadTransform(Kind(42), upgradeUser, nil))
+	assert.Error(t, err)
+}
+
+func TestWithResponseTransform(t *testing.T) {
+	addVersion := func(status int, body []byte) []byte {
+		var obj map[string]any
+		if err := json.Unmarshal(body, &obj); err != nil {
+			return body
+		}
+		obj["api_version"] = "v1"
+		obj["status"] = status
+		out, err := json.Marshal(obj)
+		if err != nil {
+			return body
+		}
+		return out
+	}
+
+	server, err := NewServer(WithResponseTransform(addVersion))
+	assert.NoError(t, err)
+
+	rr := NewRouters()
+	_ = rr.AddRouter("/users", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.JSON(http.StatusOK, map[string]string{"name": "Ada"})
+		},
+		http.MethodPost: func(c Context) error {
+			return echo.NewHTTPError(http.StatusConflict, "already exists")
+		},
+	})
+	_ = rr.AddRouter("/text", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "plain")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name         string
+		method       string
+		path         string
+		expectedCode int
+		expectedBody string
+	}{
+		{"JSON response", http.MethodGet, "/users", http.StatusOK, `{"name":"Ada","api_version":"v1","status":200}`},
+		{"Error response", http.MethodPost, "/users", http.StatusConflict, `{"message":"already exists","api_version":"v1","status":409}`},
+		{"Not found", http.MethodGet, "/missing", http.StatusNotFound, `{"message":"Not Found","api_version":"v1","status":404}`},
+		{"Non JSON response", http.MethodGet, "/text", http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			if len(tt.expectedBody) > 0 {
+				assert.JSONEq(t, tt.expectedBody, rec.Body.String())
+			} else {
+				assert.Equal(t, "plain", rec.Body.String())
+			}
+		})
+	}
+}
@@ -0,0 +1,46 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/labstack/echo/v4"
+)
+
+// requireProtocol answers 426 Upgrade Required to requests made over an
+// HTTP version older than proto, or over TLS older than minTLS, naming the
+// protocol to switch to in the Upgrade header instead of serving them.
+// Empty proto and zero minTLS disable the respective check; plain HTTP
+// requests are not subject to minTLS.
+func requireProtocol(proto string, minTLS uint16) MiddlewareFunc {
+	major, minor, _ := http.ParseHTTPVersion(proto)
+
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			req := c.Request()
+
+			upgrade := ""
+			switch {
+			case len(proto) > 0 && !req.ProtoAtLeast(major, minor):
+				upgrade = proto
+			case minTLS > 0 && req.TLS != nil && req.TLS.Version < minTLS:
+				upgrade = tlsProtocol(minTLS)
+				if len(proto) > 0 {
+					upgrade += ", " + proto
+				}
+			default:
+				return next(c)
+			}
+
+			c.Response().Header().Set("Upgrade", upgrade)
+			c.Response().Header().Set(echo.HeaderConnection, "Upgrade")
+			return echo.NewHTTPError(http.StatusUpgradeRequired, "upgrade to "+upgrade+" required")
+		}
+	}
+}
+
+// tlsProtocol renders a TLS version as an Upgrade protocol token, e.g.
+// TLS/1.2
+func tlsProtocol(version uint16) string {
+	return fmt.Sprintf("TLS/1.%d", version-0x0301)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUpgradeRequired(t *testing.T) {
+	handler := func(c Context) error { return c.String(http.StatusOK, "ok") }
+
+	tests := []struct {
+		name            string
+		opts            []Options
+		proto           string
+		tlsVersion      uint16
+		expectedCode    int
+		expectedUpgrade string
+	}{
+		{"http/1.0 refused", []Options{WithUpgradeRequired("HTTP/1.1", 0)}, "HTTP/1.0", 0, http.StatusUpgradeRequired, "HTTP/1.1"},
+		{"http/1.1 served", []Options{WithUpgradeRequired("HTTP/1.1", 0)}, "HTTP/1.1", 0, http.StatusOK, ""},
+		{"http/2 served", []Options{WithUpgradeRequired("HTTP/1.1", 0)}, "HTTP/2.0", 0, http.StatusOK, ""},
+		{"http/1.0 not configured", nil, "HTTP/1.0", 0, http.StatusOK, ""},
+		{"old tls refused", []Options{WithUpgradeRequired("", tls.VersionTLS12)}, "HTTP/1.1", tls.VersionTLS11, http.StatusUpgradeRequired, "TLS/1.2"},
+		{"old tls with protocol", []Options{WithUpgradeRequired("HTTP/1.1", tls.VersionTLS12)}, "HTTP/1.1", tls.VersionTLS10, http.StatusUpgradeRequired, "TLS/1.2, HTTP/1.1"},
+		{"current tls served", []Options{WithUpgradeRequired("", tls.VersionTLS12)}, "HTTP/1.1", tls.VersionTLS13, http.StatusOK, ""},
+		{"plain http served", []Options{WithUpgradeRequired("", tls.VersionTLS12)}, "HTTP/1.1", 0, http.StatusOK, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server, err := NewServer(tt.opts...)
+			if !assert.NoError(t, err) {
+				return
+			}
+			rr := NewRouters()
+			rr.AddRouter("/", Methods{http.MethodGet: handler})
+			_ = server.RegisterRouters(ROOT, rr)
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Proto = tt.proto
+			req.ProtoMajor, req.ProtoMinor, _ = http.ParseHTTPVersion(tt.proto)
+			if tt.tlsVersion > 0 {
+				req.TLS = &tls.ConnectionState{Version: tt.tlsVersion}
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedUpgrade, rec.Header().Get("Upgrade"))
+			if tt.expectedCode == http.StatusUpgradeRequired {
+				assert.Equal(t, "Upgrade", rec.Header().Get("Connection"))
+			}
+		})
+	}
+}
+
+func TestUpgradeRequiredInvalid(t *testing.T) {
+	_, err := NewServer(WithUpgradeRequired("", 0))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithUpgradeRequired("HTTP/x", 0))
+	assert.Error(t, err)
+
+	_, err = NewServer(WithUpgradeRequired("", 0x0200))
+	assert.Error(t, err)
+}
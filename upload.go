@@ -0,0 +1,193 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"os"
+
+	"github.com/gookit/slog"
+	"github.com/labstack/echo/v4"
+)
+
+// uploadsKey holds the uploads received during a request
+const uploadsKey = "echowr.uploads"
+
+// UploadOptions limits ReceiveUpload. Zero limits are unlimited.
+type UploadOptions struct {
+	// Dir receives the temp files, os.TempDir() when empty
+	Dir string
+
+	// MaxPartSize caps each part, files and values alike
+	MaxPartSize int64
+
+	// MaxTotalSize caps the sum of all parts
+	MaxTotalSize int64
+}
+
+// UploadedFile is a file part streamed to a temp file. File is open and
+// positioned at the start of the content.
+type UploadedFile struct {
+	Field    string
+	Filename string
+	Header   textproto.MIMEHeader
+	Size     int64
+	Path     string
+	File     *os.File
+}
+
+// Upload is a multipart form received by ReceiveUpload
+type Upload struct {
+	Values map[string][]string
+	Files  []*UploadedFile
+}
+
+// ReceiveUpload streams a multipart request part by part, writing files to
+// temp files instead of memory so large uploads don't exhaust it. Parts
+// over a limit answer 413. The temp files are removed when the handler
+// returns, or earlier with Close; on error nothing is left behind.
+func ReceiveUpload(c Context, opts UploadOptions) (*Upload, error) {
+	reader, err := c.Request().MultipartReader()
+	if err != nil {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid multipart request").SetInternal(err)
+	}
+
+	upload := &Upload{Values: map[string][]string{}}
+	var total int64
+
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			uploads, _ := c.Get(uploadsKey).([]*Upload)
+			c.Set(uploadsKey, append(uploads, upload))
+			return upload, nil
+		}
+		if err != nil {
+			upload.Close()
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "invalid multipart request").SetInternal(err)
+		}
+
+		// the part may use whatever is left of the total, -1 for no limit
+		limit := int64(-1)
+		if opts.MaxPartSize > 0 {
+			limit = opts.MaxPartSize
+		}
+		if rest := opts.MaxTotalSize - total; opts.MaxTotalSize > 0 && (limit < 0 || rest < limit) {
+			limit = rest
+		}
+
+		var n int64
+		if len(part.FileName()) == 0 {
+			n, err = upload.readValue(part, limit)
+		} else {
+			n, err = upload.readFile(part, opts.Dir, limit)
+		}
+		part.Close()
+		if err != nil {
+			upload.Close()
+			return nil, err
+		}
+
+		total += n
+	}
+}
+
+// readValue keeps a non-file part in memory
+func (u *Upload) readValue(part *multipart.Part, limit int64) (int64, error) {
+	value, err := io.ReadAll(limitPart(part, limit))
+	if err != nil {
+		return 0, err
+	}
+	if err := checkPartSize(int64(len(value)), limit); err != nil {
+		return 0, err
+	}
+
+	name := part.FormName()
+	u.Values[name] = append(u.Values[name], string(value))
+	return int64(len(value)), nil
+}
+
+// readFile streams a file part to a temp file in dir
+func (u *Upload) readFile(part *multipart.Part, dir string, limit int64) (int64, error) {
+	f, err := os.CreateTemp(dir, "upload-*")
+	if err != nil {
+		return 0, fmt.Errorf("create upload file: %w", err)
+	}
+
+	file := &UploadedFile{
+		Field:    part.FormName(),
+		Filename: part.FileName(),
+		Header:   part.Header,
+		Path:     f.Name(),
+		File:     f,
+	}
+	// track the file first so Close removes it whatever happens next
+	u.Files = append(u.Files, file)
+
+	n, err := io.Copy(f, limitPart(part, limit))
+	if err != nil {
+		return 0, fmt.Errorf("write upload file: %w", err)
+	}
+	if err := checkPartSize(n, limit); err != nil {
+		return 0, err
+	}
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	file.Size = n
+	return n, nil
+}
+
+// Close closes and removes the temp files of the upload
+func (u *Upload) Close() error {
+	var errs []error
+	for _, file := range u.Files {
+		file.File.Close()
+		if err := os.Remove(file.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			errs = append(errs, err)
+		}
+	}
+	u.Files = nil
+	return errors.Join(errs...)
+}
+
+// cleanupUploads removes the temp files of the uploads received by the
+// handler once it returns
+func (s *Server) cleanupUploads() MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			defer func() {
+				uploads, _ := c.Get(uploadsKey).([]*Upload)
+				for _, upload := range uploads {
+					if err := upload.Close(); err != nil {
+						s.log(slog.WarnLevel, "upload cleanup failed", slog.M{
+							"error": err.Error(),
+							"path":  c.Request().URL.Path,
+						})
+					}
+				}
+			}()
+			return next(c)
+		}
+	}
+}
+
+// limitPart reads one byte past limit so oversized parts can be detected
+func limitPart(r io.Reader, limit int64) io.Reader {
+	if limit < 0 {
+		return r
+	}
+	return io.LimitReader(r, limit+1)
+}
+
+// checkPartSize fails with 413 when n went past a non negative limit
+func checkPartSize(n, limit int64) error {
+	if limit >= 0 && n > limit {
+		return echo.NewHTTPError(http.StatusRequestEntityTooLarge, "upload too large")
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+package server
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+// multipartBody builds a form with a title value and the given files
+func multipartBody(t *testing.T, files map[string]string) (*bytes.Buffer, string) {
+	var buf bytes.Buffer
+	w := multipart.NewWriter(&buf)
+	assert.NoError(t, w.WriteField("title", "holiday"))
+	for name, content := range files {
+		fw, err := w.CreateFormFile("photos", name)
+		assert.NoError(t, err)
+		_, _ = io.WriteString(fw, content)
+	}
+	assert.NoError(t, w.Close())
+	return &buf, w.FormDataContentType()
+}
+
+func TestReceiveUpload(t *testing.T) {
+	dir := t.TempDir()
+	large := strings.Repeat("a", 2<<20)
+
+	var paths []string
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/upload", Methods{
+		http.MethodPost: func(c Context) error {
+			upload, err := ReceiveUpload(c, UploadOptions{Dir: dir, MaxPartSize: 4 << 20})
+			if err != nil {
+				return err
+			}
+
+			// no Close: the files must be removed when the handler returns
+			assert.Equal(t, []string{"holiday"}, upload.Values["title"])
+			if assert.Len(t, upload.Files, 2) {
+				for _, f := range upload.Files {
+					paths = append(paths, f.Path)
+					assert.Equal(t, "photos", f.Field)
+					assert.Equal(t, int64(len(large)), f.Size)
+					assert.FileExists(t, f.Path)
+
+					content, err := io.ReadAll(f.File)
+					assert.NoError(t, err)
+					assert.Equal(t, large, string(content))
+				}
+			}
+			return c.NoContent(http.StatusNoContent)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	body, contentType := multipartBody(t, map[string]string{"a.jpg": large, "b.jpg": large})
+	req := httptest.NewRequest(http.MethodPost, "/upload", body)
+	req.Header.Set(echo.HeaderContentType, contentType)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Len(t, paths, 2)
+	for _, path := range paths {
+		assert.NoFileExists(t, path)
+	}
+}
+
+func TestReceiveUploadLimits(t *testing.T) {
+	tests := []struct {
+		name string
+		opts UploadOptions
+	}{
+		{"Part too large", UploadOptions{MaxPartSize: 1024}},
+		{"Total too large", UploadOptions{MaxTotalSize: 3000}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.opts.Dir = t.TempDir()
+
+			server, _ := NewServer()
+			rr := NewRouters()
+			rr.AddRouter("/upload", Methods{
+				http.MethodPost: func(c Context) error {
+					upload, err := ReceiveUpload(c, tt.opts)
+					if err != nil {
+						return err
+					}
+					defer upload.Close()
+					return c.NoContent(http.StatusNoContent)
+				},
+			})
+			_ = server.RegisterRouters(ROOT, rr)
+
+			body, contentType := multipartBody(t, map[string]string{
+				"a.txt": strings.Repeat("a", 1000),
+				"b.txt": strings.Repeat("b", 2000),
+			})
+			req := httptest.NewRequest(http.MethodPost, "/upload", body)
+			req.Header.Set(echo.HeaderContentType, contentType)
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusRequestEntityTooLarge, rec.Code)
+
+			entries, err := os.ReadDir(tt.opts.Dir)
+			assert.NoError(t, err)
+			assert.Empty(t, entries)
+		})
+	}
+}
+
+func TestReceiveUploadNotMultipart(t *testing.T) {
+	server, _ := NewServer()
+	c := server.GetEcho().NewContext(httptest.NewRequest(http.MethodPost, "/", strings.NewReader("{}")), httptest.NewRecorder())
+
+	_, err := ReceiveUpload(c, UploadOptions{})
+	var he *echo.HTTPError
+	if assert.ErrorAs(t, err, &he) {
+		assert.Equal(t, http.StatusBadRequest, he.Code)
+	}
+}
@@ -0,0 +1,139 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// Validator validates bound request values, see WithValidator
+type Validator = echo.Validator
+
+// FieldError describes why a single field failed validation
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is the body of the 400 returned by BindValidate when the
+// request value fails validation
+type ValidationError struct {
+	Message string       `json:"message"`
+	Fields  []FieldError `json:"fields,omitempty"`
+}
+
+// structValidator adapts go-playground/validator to echo, reporting fields
+// by their JSON name
+type structValidator struct {
+	validate *validator.Validate
+}
+
+// NewStructValidator returns a Validator running the `validate` struct tags
+// of go-playground/validator. It is used by BindValidate when WithValidator
+// is not set.
+func NewStructValidator() Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		switch name {
+		case "-":
+			return ""
+		case "":
+			return f.Name
+		}
+		return name
+	})
+	return &structValidator{validate: v}
+}
+
+// Validate checks structs and pointers to structs, any other value has no
+// tags to validate and passes
+func (v *structValidator) Validate(i any) error {
+	if reflect.Indirect(reflect.ValueOf(i)).Kind() != reflect.Struct {
+		return nil
+	}
+	return v.validate.Struct(i)
+}
+
+var defaultValidator = NewStructValidator()
+
+// BindValidate binds the request into v like Context.Bind, then validates
+// it with the server Validator. A failed validation is returned as a 400
+// HTTPError whose message is a ValidationError listing every invalid field.
+func BindValidate(c Context, v any) error {
+	if err := c.Bind(v); err != nil {
+		return err
+	}
+	return validateBound(c, v)
+}
+
+// validateBound validates a value bound from the request with the server
+// Validator, or the default struct validator when none is set, turning a
+// failure into a 400 carrying a ValidationError
+func validateBound(c Context, v any) error {
+	validate := c.Echo().Validator
+	if validate == nil {
+		validate = defaultValidator
+	}
+
+	err := validate.Validate(v)
+	if err == nil {
+		return nil
+	}
+
+	var (
+		he     *echo.HTTPError
+		fields validator.ValidationErrors
+	)
+	switch {
+	case errors.As(err, &he):
+		return he
+	case errors.As(err, &fields):
+		body := &ValidationError{Message: "validation failed"}
+		for _, f := range fields {
+			body.Fields = append(body.Fields, FieldError{
+				Field:   fieldPath(f),
+				Message: fieldMessage(f),
+			})
+		}
+		return echo.NewHTTPError(http.StatusBadRequest, body).SetInternal(err)
+	}
+
+	return echo.NewHTTPError(http.StatusBadRequest, &ValidationError{Message: err.Error()}).SetInternal(err)
+}
+
+// fieldPath returns the field namespace without the root struct name, e.g.
+// "address.city"
+func fieldPath(f validator.FieldError) string {
+	_, path, ok := strings.Cut(f.Namespace(), ".")
+	if !ok {
+		return f.Field()
+	}
+	return path
+}
+
+// fieldMessage renders a readable message for the common tags
+func fieldMessage(f validator.FieldError) string {
+	switch f.Tag() {
+	case "required":
+		return "is required"
+	case "email":
+		return "must be a valid email"
+	case "min", "gte":
+		return "must be at least " + f.Param()
+	case "max", "lte":
+		return "must be at most " + f.Param()
+	case "oneof":
+		return "must be one of " + f.Param()
+	}
+
+	if len(f.Param()) > 0 {
+		return fmt.Sprintf("failed %s=%s", f.Tag(), f.Param())
+	}
+	return "failed " + f.Tag()
+}
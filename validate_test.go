@@ -0,0 +1,89 @@
+package server
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+type validateAddress struct {
+	City string `json:"city" validate:"required"`
+}
+
+type validateUser struct {
+	Name    string          `json:"name" validate:"required"`
+	Email   string          `json:"email" validate:"required,email"`
+	Age     int             `json:"age" validate:"gte=18"`
+	Address validateAddress `json:"address"`
+}
+
+func validateServer(opts ...Options) *Server {
+	server, _ := NewServer(opts...)
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodPost: func(c Context) error {
+			var u validateUser
+			if err := BindValidate(c, &u); err != nil {
+				return err
+			}
+			return c.JSON(http.StatusCreated, u)
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+	return server
+}
+
+func postUser(server *Server, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+	server.GetEcho().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestBindValidate(t *testing.T) {
+	server := validateServer()
+
+	valid := `{"name":"Ana","email":"ana@example.com","age":30,"address":{"city":"Recife"}}`
+	rec := postUser(server, valid)
+	assert.Equal(t, http.StatusCreated, rec.Code)
+	assert.JSONEq(t, valid, rec.Body.String())
+
+	rec = postUser(server, `{"email":"not-an-email","age":12}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{
+		"message": "validation failed",
+		"fields": [
+			{"field": "name", "message": "is required"},
+			{"field": "email", "message": "must be a valid email"},
+			{"field": "age", "message": "must be at least 18"},
+			{"field": "address.city", "message": "is required"}
+		]
+	}`, rec.Body.String())
+
+	rec = postUser(server, `{"name":`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "malformed JSON")
+}
+
+type rejectAll struct{}
+
+func (rejectAll) Validate(any) error {
+	return errors.New("rejected")
+}
+
+func TestBindValidateCustomValidator(t *testing.T) {
+	server := validateServer(WithValidator(rejectAll{}))
+
+	rec := postUser(server, `{"name":"Ana","email":"ana@example.com","age":30,"address":{"city":"Recife"}}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.JSONEq(t, `{"message":"rejected"}`, rec.Body.String())
+
+	_, err := NewServer(WithValidator(nil))
+	assert.Error(t, err)
+}
@@ -0,0 +1,111 @@
+package server
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderAcceptVersion is the default header used to select an API version
+const HeaderAcceptVersion = "Accept-Version"
+
+// VersionedHandler dispatches to the handler matching the Accept-Version
+// header, defaulting to the latest version when the header is absent and
+// answering 406 for unknown versions
+func VersionedHandler(versions map[string]HandlerFunc) HandlerFunc {
+	return VersionedHandlerHeader(HeaderAcceptVersion, versions)
+}
+
+// VersionedHandlerHeader is like VersionedHandler but reads the version from
+// the given header
+func VersionedHandlerHeader(header string, versions map[string]HandlerFunc) HandlerFunc {
+	latest := latestVersion(versions)
+
+	return func(c Context) error {
+		version := strings.TrimSpace(c.Request().Header.Get(header))
+		if len(version) == 0 {
+			version = latest
+		}
+
+		handler, ok := versions[version]
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotAcceptable, "unsupported version: "+version)
+		}
+
+		c.Response().Header().Add(echo.HeaderVary, header)
+		return handler(c)
+	}
+}
+
+// VersionTransform adapts an older API version to the current one: Up
+// rewrites its request bodies, Down rewrites the current response bodies
+// back to its shape
+type VersionTransform struct {
+	Up   PayloadTransformFunc
+	Down PayloadTransformFunc
+}
+
+// VersionedTransformHandler serves every version from a single handler
+// written against the current version. Requests asking for one of the
+// older versions in transforms are converted on the way in and out, the
+// current version and requests without Accept-Version reach the handler
+// untouched. Transforms are meant for versions older than current.
+func VersionedTransformHandler(current string, handler HandlerFunc, transforms map[string]VersionTransform) HandlerFunc {
+	versions := map[string]HandlerFunc{current: handler}
+	for version, t := range transforms {
+		versions[version] = transformPayload(t.Up, t.Down)(handler)
+	}
+	return VersionedHandler(versions)
+}
+
+// latestVersion returns the highest version key, comparing dotted numeric
+// segments ("v2" < "v10", "1.2" < "1.10")
+func latestVersion(versions map[string]HandlerFunc) string {
+	keys := make([]string, 0, len(versions))
+	for k := range versions {
+		keys = append(keys, k)
+	}
+
+	sort.Slice(keys, func(i, j int) bool {
+		return compareVersions(keys[i], keys[j]) < 0
+	})
+
+	if len(keys) == 0 {
+		return ""
+	}
+	return keys[len(keys)-1]
+}
+
+// compareVersions compares two versions segment by segment, numerically
+// when both segments are numbers
+func compareVersions(a, b string) int {
+	as := strings.Split(strings.TrimPrefix(strings.ToLower(a), "v"), ".")
+	bs := strings.Split(strings.TrimPrefix(strings.ToLower(b), "v"), ".")
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, xerr := strconv.Atoi(x)
+		yn, yerr := strconv.Atoi(y)
+		switch {
+		case xerr == nil && yerr == nil && xn != yn:
+			if xn < yn {
+				return -1
+			}
+			return 1
+		case (xerr != nil || yerr != nil) && x != y:
+			return strings.Compare(x, y)
+		}
+	}
+
+	return 0
+}
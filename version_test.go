@@ -0,0 +1,163 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVersionedHandler(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodGet: VersionedHandler(map[string]HandlerFunc{
+			"v1": func(c Context) error {
+				return c.String(http.StatusOK, "users v1")
+			},
+			"v2": func(c Context) error {
+				return c.String(http.StatusOK, "users v2")
+			},
+			"v10": func(c Context) error {
+				return c.String(http.StatusOK, "users v10")
+			},
+		}),
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	tests := []struct {
+		name         string
+		version      string
+		expectedCode int
+		expectedBody string
+	}{
+		{"Version v1", "v1", http.StatusOK, "users v1"},
+		{"Version v2", "v2", http.StatusOK, "users v2"},
+		{"Default to latest", "", http.StatusOK, "users v10"},
+		{"Unknown version", "v3", http.StatusNotAcceptable, `{"message":"unsupported version: v3"}` + "\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/users", nil)
+			if tt.version != "" {
+				req.Header.Set(HeaderAcceptVersion, tt.version)
+			}
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.expectedCode, rec.Code)
+			assert.Equal(t, tt.expectedBody, rec.Body.String())
+		})
+	}
+}
+
+func TestVersionedHandlerHeader(t *testing.T) {
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodGet: VersionedHandlerHeader("X-Api-Version", map[string]HandlerFunc{
+			"1.2": func(c Context) error {
+				return c.String(http.StatusOK, "1.2")
+			},
+			"1.10": func(c Context) error {
+				return c.String(http.StatusOK, "1.10")
+			},
+		}),
+	})
+
+	_ = server.RegisterRouters(ROOT, rr)
+
+	e := server.GetEcho()
+
+	req := httptest.NewRequest(http.MethodGet, "/users", nil)
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "1.10", rec.Body.String())
+	assert.Equal(t, "X-Api-Version", rec.Header().Get("Vary"))
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("X-Api-Version", "1.2")
+	rec = httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	assert.Equal(t, "1.2", rec.Body.String())
+}
+
+func TestVersionedTransformHandler(t *testing.T) {
+	// rename returns a transform renaming a top level JSON field
+	rename := func(from, to string) PayloadTransformFunc {
+		return func(body []byte) ([]byte, error) {
+			var m map[string]any
+			if err := json.Unmarshal(body, &m); err != nil {
+				return nil, err
+			}
+			if v, ok := m[from]; ok {
+				m[to] = v
+				delete(m, from)
+			}
+			return json.Marshal(m)
+		}
+	}
+
+	type user struct {
+		ID       int    `json:"id"`
+		FullName string `json:"full_name"`
+	}
+
+	calls := 0
+	handler := func(c Context) error {
+		calls++
+		u := new(user)
+		if err := c.Bind(u); err != nil {
+			return err
+		}
+		u.ID = 1
+		return c.JSON(http.StatusCreated, u)
+	}
+
+	server, _ := NewServer()
+	rr := NewRouters()
+	rr.AddRouter("/users", Methods{
+		http.MethodPost: VersionedTransformHandler("v2", handler, map[string]VersionTransform{
+			"v1": {Up: rename("name", "full_name"), Down: rename("full_name", "name")},
+		}),
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	tests := []struct {
+		name     string
+		version  string
+		body     string
+		expected string
+		status   int
+	}{
+		{"V1 client", "v1", `{"name":"Ada"}`, `{"id":1,"name":"Ada"}`, http.StatusCreated},
+		{"V2 client", "v2", `{"full_name":"Ada"}`, `{"id":1,"full_name":"Ada"}`, http.StatusCreated},
+		{"Default to current", "", `{"full_name":"Ada"}`, `{"id":1,"full_name":"Ada"}`, http.StatusCreated},
+		{"Unknown version", "v3", `{"full_name":"Ada"}`, `{"message":"unsupported version: v3"}`, http.StatusNotAcceptable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+			if len(tt.version) > 0 {
+				req.Header.Set(HeaderAcceptVersion, tt.version)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, tt.status, rec.Code)
+			assert.JSONEq(t, tt.expected, rec.Body.String())
+		})
+	}
+
+	assert.Equal(t, 3, calls)
+}
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/gookit/slog"
+	"github.com/gorilla/websocket"
+)
+
+const (
+	defaultWebSocketReadLimit = 1 << 20
+	defaultWebSocketPing      = 30 * time.Second
+)
+
+// WebSocketHandler serves an upgraded connection until it returns. The
+// connection is closed afterwards and a returned error is logged.
+type WebSocketHandler func(conn *websocket.Conn) error
+
+// AddWebSocket adds a GET route upgrading requests to a WebSocket handled
+// by handler, failing if GET is already registered for the path
+func (r *RegisterRouters) AddWebSocket(path string, handler WebSocketHandler) error {
+	if handler == nil {
+		return fmt.Errorf("websocket handler for %s is nil", path)
+	}
+
+	return r.add(RegisterRouter{
+		Path:      path,
+		Methods:   Methods{http.MethodGet: notUpgraded},
+		WebSocket: handler,
+	})
+}
+
+// notUpgraded stands in for the GET handler of a WebSocket router until the
+// server registers it
+func notUpgraded(c Context) error {
+	return fmt.Errorf("websocket route %s was not registered through the server", c.Path())
+}
+
+// webSockets tracks the open connections so shutdown can close them
+type webSockets struct {
+	mu    sync.Mutex
+	conns map[*websocket.Conn]struct{}
+}
+
+func (w *webSockets) add(conn *websocket.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conns == nil {
+		w.conns = map[*websocket.Conn]struct{}{}
+	}
+	w.conns[conn] = struct{}{}
+}
+
+func (w *webSockets) remove(conn *websocket.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.conns, conn)
+}
+
+// closeAll sends a going away close frame to every open connection and
+// closes it, making their handlers return
+func (w *webSockets) closeAll() {
+	w.mu.Lock()
+	conns := make([]*websocket.Conn, 0, len(w.conns))
+	for conn := range w.conns {
+		conns = append(conns, conn)
+	}
+	w.mu.Unlock()
+
+	msg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for _, conn := range conns {
+		_ = conn.WriteControl(websocket.CloseMessage, msg, time.Now().Add(time.Second))
+		conn.Close()
+	}
+}
+
+// webSocket upgrades the request and runs handler with a read limit and a
+// ping/pong keepalive: a peer that stops answering pings is disconnected
+func (s *Server) webSocket(handler WebSocketHandler) HandlerFunc {
+	limit := s.params.GetWebSocketReadLimit()
+	if limit == 0 {
+		limit = defaultWebSocketReadLimit
+	}
+	ping := s.params.GetWebSocketPing()
+	if ping == 0 {
+		ping = defaultWebSocketPing
+	}
+	pongWait := 2 * ping
+
+	upgrader := websocket.Upgrader{}
+
+	return func(c Context) error {
+		// the upgrader answers failed handshakes itself
+		conn, err := upgrader.Upgrade(c.Response(), c.Request(), nil)
+		if err != nil {
+			return nil
+		}
+
+		s.sockets.add(conn)
+		defer func() {
+			s.sockets.remove(conn)
+			conn.Close()
+		}()
+
+		conn.SetReadLimit(limit)
+		_ = conn.SetReadDeadline(time.Now().Add(pongWait))
+		conn.SetPongHandler(func(string) error {
+			return conn.SetReadDeadline(time.Now().Add(pongWait))
+		})
+
+		done := make(chan struct{})
+		defer close(done)
+		go func() {
+			ticker := time.NewTicker(ping)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-done:
+					return
+				case <-ticker.C:
+					if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(ping)); err != nil {
+						return
+					}
+				}
+			}
+		}()
+
+		// the connection is hijacked, so errors can only be logged
+		if err := handler(conn); err != nil {
+			s.log(slog.WarnLevel, "websocket error", slog.M{
+				"path":  c.Request().URL.Path,
+				"route": c.Path(),
+				"error": err.Error(),
+			})
+		}
+		return nil
+	}
+}
@@ -0,0 +1,144 @@
+package server
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+)
+
+// dialWebSocket starts server and connects to the WebSocket at path
+func dialWebSocket(t *testing.T, server *Server, path string) *websocket.Conn {
+	server.Start()
+	<-server.Listening()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws://"+server.Addr()+path, nil)
+	if !assert.NoError(t, err) {
+		t.FailNow()
+	}
+	return conn
+}
+
+func TestWebSocketEcho(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithWebSocketConfig(64, 50*time.Millisecond))
+	defer server.Close()
+
+	rr := NewRouters()
+	assert.NoError(t, rr.AddWebSocket("/echo", func(conn *websocket.Conn) error {
+		for {
+			kind, msg, err := conn.ReadMessage()
+			if err != nil {
+				return nil
+			}
+			if err := conn.WriteMessage(kind, msg); err != nil {
+				return err
+			}
+		}
+	}))
+	assert.Error(t, rr.AddRouter("/echo", Methods{http.MethodGet: func(c Context) error { return nil }}))
+	assert.NoError(t, server.RegisterRouters(ROOT, rr))
+
+	conn := dialWebSocket(t, server, "/echo")
+	defer conn.Close()
+
+	pings := make(chan struct{}, 10)
+	conn.SetPingHandler(func(data string) error {
+		pings <- struct{}{}
+		return conn.WriteControl(websocket.PongMessage, []byte(data), time.Now().Add(time.Second))
+	})
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("hello")))
+	_, msg, err := conn.ReadMessage()
+	assert.NoError(t, err)
+	assert.Equal(t, "hello", string(msg))
+
+	// ping handlers only run while reading, so keep a read pending
+	go func() {
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+	select {
+	case <-pings:
+	case <-time.After(time.Second):
+		t.Fatal("no keepalive ping received")
+	}
+
+	// a message over the read limit closes the connection
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat("x", 128))))
+}
+
+func TestWebSocketReadLimit(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"), WithWebSocketConfig(64, time.Second))
+	defer server.Close()
+
+	rr := NewRouters()
+	_ = rr.AddWebSocket("/ws", func(conn *websocket.Conn) error {
+		_, _, err := conn.ReadMessage()
+		return err
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	conn := dialWebSocket(t, server, "/ws")
+	defer conn.Close()
+
+	assert.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(strings.Repeat("x", 128))))
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseMessageTooBig), "unexpected error: %v", err)
+}
+
+func TestWebSocketClosedOnShutdown(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+
+	returned := make(chan struct{})
+	rr := NewRouters()
+	_ = rr.AddWebSocket("/ws", func(conn *websocket.Conn) error {
+		defer close(returned)
+		for {
+			if _, _, err := conn.ReadMessage(); err != nil {
+				return nil
+			}
+		}
+	})
+	_ = server.RegisterRouters(ROOT, rr)
+
+	conn := dialWebSocket(t, server, "/ws")
+	defer conn.Close()
+
+	assert.NoError(t, server.GracefulShutdown())
+
+	_ = conn.SetReadDeadline(time.Now().Add(time.Second))
+	_, _, err := conn.ReadMessage()
+	assert.True(t, websocket.IsCloseError(err, websocket.CloseGoingAway), "unexpected error: %v", err)
+
+	select {
+	case <-returned:
+	case <-time.After(time.Second):
+		t.Fatal("handler still running after shutdown")
+	}
+}
+
+func TestWebSocketHandshakeRequired(t *testing.T) {
+	server, _ := NewServer(WithHost("127.0.0.1"), WithPort("0"))
+	defer server.Close()
+
+	rr := NewRouters()
+	_ = rr.AddWebSocket("/ws", func(conn *websocket.Conn) error { return nil })
+	_ = server.RegisterRouters(ROOT, rr)
+
+	server.Start()
+	<-server.Listening()
+
+	resp, err := http.Get("http://" + server.Addr() + "/ws")
+	if assert.NoError(t, err) {
+		resp.Body.Close()
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+	}
+}
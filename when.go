@@ -0,0 +1,16 @@
+package server
+
+// When wraps a middleware so it only runs for requests matching cond, e.g.
+// verbose logging only when a debug header is present. Other requests go
+// straight to the next handler.
+func When(cond func(c Context) bool, mw MiddlewareFunc) MiddlewareFunc {
+	return func(next HandlerFunc) HandlerFunc {
+		wrapped := mw(next)
+		return func(c Context) error {
+			if cond(c) {
+				return wrapped(c)
+			}
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWhen(t *testing.T) {
+	server, _ := NewServer()
+
+	ran := false
+	inner := func(next HandlerFunc) HandlerFunc {
+		return func(c Context) error {
+			ran = true
+			c.Response().Header().Set("X-Debug", "on")
+			return next(c)
+		}
+	}
+	debug := func(c Context) bool {
+		return c.Request().Header.Get("X-Debug") == "1"
+	}
+
+	rr := NewRouters()
+	rr.AddRouter("/test", Methods{
+		http.MethodGet: func(c Context) error {
+			return c.String(http.StatusOK, "test passed")
+		},
+	})
+	_ = server.RegisterRouters(ROOT, rr, When(debug, inner))
+
+	tests := []struct {
+		name     string
+		header   string
+		expected bool
+	}{
+		{"Condition holds", "1", true},
+		{"Condition fails", "0", false},
+		{"No header", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ran = false
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if len(tt.header) > 0 {
+				req.Header.Set("X-Debug", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			server.GetEcho().ServeHTTP(rec, req)
+
+			assert.Equal(t, http.StatusOK, rec.Code)
+			assert.Equal(t, "test passed", rec.Body.String())
+			assert.Equal(t, tt.expected, ran)
+			assert.Equal(t, tt.expected, rec.Header().Get("X-Debug") == "on")
+		})
+	}
+}